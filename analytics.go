@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type analyticsReq struct {
+	region   string
+	name     string
+	logGroup string
+}
+
+type analyticsMsg struct {
+	stats   invocationStats
+	streams int
+}
+
+// analyticsStreams is the number of recently active log streams whose REPORT
+// lines are analysed.
+const analyticsStreams = 20
+
+// invocationStats summarises the REPORT lines of invocations. The durations
+// and memory usages are sorted in ascending order.
+type invocationStats struct {
+	invocations   int
+	coldStarts    int
+	durations     []float64
+	initDurations []float64
+	memoryUsed    []float64
+	// memorySize is the configured memory size of the function in MB.
+	memorySize float64
+}
+
+var (
+	analyticsBarStyle  = sparklineStyle
+	analyticsHintStyle = lipgloss.NewStyle().MarginLeft(1)
+)
+
+// getRecentInvocations returns the invocations of the most recent page of
+// events of the recently active streams of a log group, along with the number
+// of streams read.
+func getRecentInvocations(ctx context.Context, c cloudwatchLogsAPI, logGroup string) ([]invocation, int, error) {
+	streams, _, err := getLogStreams(ctx, c, logGroup, "", nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	streams = slices.DeleteFunc(streams, func(s logStream) bool { return s.expired })
+	streams = streams[:min(len(streams), analyticsStreams)]
+
+	invocations := make([]invocation, 0)
+	for _, stream := range streams {
+		page, err := getLogEvents(ctx, c, logGroup, stream.name, "", time.Time{})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		invocations = append(invocations, parseInvocations(page.events)...)
+	}
+
+	return invocations, len(streams), nil
+}
+
+// analyzeInvocations summarises the reported invocations. The memory size of
+// the REPORT lines is used if memorySize is zero.
+func analyzeInvocations(invocations []invocation, memorySize uint32) invocationStats {
+	stats := invocationStats{memorySize: float64(memorySize)}
+
+	for _, inv := range invocations {
+		// Only invocations with a REPORT line have been measured.
+		if inv.duration == "" {
+			continue
+		}
+
+		stats.invocations++
+		stats.durations = append(stats.durations, reportValue(inv.duration))
+		stats.memoryUsed = append(stats.memoryUsed, reportValue(inv.maxMemoryUsed))
+
+		if inv.initDuration != "" {
+			stats.coldStarts++
+			stats.initDurations = append(stats.initDurations, reportValue(inv.initDuration))
+		}
+
+		if stats.memorySize == 0 {
+			stats.memorySize = reportValue(inv.memorySize)
+		}
+	}
+
+	slices.Sort(stats.durations)
+	slices.Sort(stats.initDurations)
+	slices.Sort(stats.memoryUsed)
+
+	return stats
+}
+
+// reportValue parses a value of a REPORT line such as "12.34 ms" or "128 MB".
+func reportValue(s string) float64 {
+	number, _, _ := strings.Cut(s, " ")
+	v, _ := strconv.ParseFloat(number, 64)
+
+	return v
+}
+
+// percentile returns the nearest-rank percentile p of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	return sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)]
+}
+
+// durationSummary describes the percentiles of sorted durations in ms.
+func durationSummary(sorted []float64) string {
+	if len(sorted) == 0 {
+		return "no data"
+	}
+
+	return fmt.Sprintf(
+		"p50 %s • p90 %s • p99 %s • max %s",
+		formatMetricValue(percentile(sorted, 0.5), "ms"),
+		formatMetricValue(percentile(sorted, 0.9), "ms"),
+		formatMetricValue(percentile(sorted, 0.99), "ms"),
+		formatMetricValue(sorted[len(sorted)-1], "ms"),
+	)
+}
+
+// memoryHistogram renders the number of invocations per tenth of the memory
+// size used, with bars of at most width blocks.
+func memoryHistogram(stats invocationStats, width int) []string {
+	counts := make([]int, 10)
+	for _, used := range stats.memoryUsed {
+		counts[min(int(used/stats.memorySize*10), 9)]++
+	}
+
+	highest := slices.Max(counts)
+	lines := make([]string, 0, len(counts))
+	for i, count := range counts {
+		bar := strings.Repeat("█", int(math.Ceil(float64(count)/float64(highest)*float64(width))))
+		label := metricSummaryStyle.Render(fmt.Sprintf("%3d-%3d%% ", i*10, (i+1)*10))
+		lines = append(lines, label+analyticsBarStyle.Render(bar)+metricSummaryStyle.Render(" "+strconv.Itoa(count)))
+	}
+
+	return lines
+}
+
+// memoryHint suggests a memory size based on the peak memory usage. Since CPU
+// is allocated in proportion to memory, lowering it may increase durations.
+func memoryHint(stats invocationStats) string {
+	peak := stats.memoryUsed[len(stats.memoryUsed)-1]
+	utilisation := peak / stats.memorySize * 100
+
+	switch {
+	case utilisation >= 90:
+		return fmt.Sprintf("Peak memory usage is %.0f%% of %.0f MB, consider raising the memory size to avoid out of memory errors.", utilisation, stats.memorySize)
+	case utilisation < 50 && stats.memorySize > 128:
+		// Leave 30% headroom, rounded up to a multiple of 64 MB.
+		suggested := max(math.Ceil(peak*1.3/64)*64, 128)
+		if suggested >= stats.memorySize {
+			break
+		}
+
+		return fmt.Sprintf("Peak memory usage is %.0f%% of %.0f MB, %.0f MB would suffice. CPU scales with memory, so check durations after lowering it.", utilisation, stats.memorySize, suggested)
+	}
+
+	return fmt.Sprintf("Peak memory usage is %.0f%% of %.0f MB, the memory size fits.", utilisation, stats.memorySize)
+}
+
+// requestAnalytics analyses the REPORT lines of the analytics target.
+func (m *model) requestAnalytics() tea.Cmd {
+	select {
+	case m.reqCh <- m.tag(analyticsReq{region: m.analyticsTarget.region, name: m.analyticsTarget.name, logGroup: m.analyticsTarget.logGroup}):
+		return m.startLoading()
+	default:
+		return nil
+	}
+}
+
+func (m model) viewAnalyticsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.activeView = viewLambda
+		case key.Matches(msg, m.keys.refresh):
+			cmd = m.requestAnalytics()
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvAnalyticsMsg(msg analyticsMsg) {
+	m.analytics = msg.stats
+	m.analyticsStreams = msg.streams
+	m.activeView = viewAnalytics
+	m.loading = false
+}
+
+func (m model) analyticsView() string {
+	stats := m.analytics
+	rows := []string{
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Analytics - Function \"%s\" - %s", m.analyticsTarget.name, m.sessionTitle())),
+		"",
+		helpStyle.Render(fmt.Sprintf("%d invocations reported in the recent events of %d log streams", stats.invocations, m.analyticsStreams)),
+	}
+
+	if stats.invocations == 0 {
+		rows = append(rows, "", helpLine(keyHelp(m.keys.refresh, ""), keyHelp(m.keys.back, "")))
+
+		return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	}
+
+	rows = append(
+		rows,
+		"",
+		metricNameStyle.Render("Cold Starts")+"  "+metricSummaryStyle.Render(fmt.Sprintf(
+			"%d of %d invocations (%.1f%%)", stats.coldStarts, stats.invocations, float64(stats.coldStarts)/float64(stats.invocations)*100,
+		)),
+		metricNameStyle.Render("Init Duration")+"  "+metricSummaryStyle.Render(durationSummary(stats.initDurations)),
+		metricNameStyle.Render("Duration")+"  "+metricSummaryStyle.Render(durationSummary(stats.durations)),
+	)
+
+	// The memory size is unknown if neither the function nor the REPORT lines
+	// state it.
+	if stats.memorySize > 0 {
+		rows = append(rows, "", metricNameStyle.Render(fmt.Sprintf("Memory Utilisation of %.0f MB", stats.memorySize)))
+
+		width := max(m.winWidth-docStyle.GetHorizontalFrameSize()-20, 1)
+		for _, line := range memoryHistogram(stats, width) {
+			rows = append(rows, " "+line)
+		}
+
+		rows = append(rows, "", analyticsHintStyle.Render(memoryHint(stats)))
+	}
+
+	rows = append(rows, "", helpLine(keyHelp(m.keys.refresh, ""), keyHelp(m.keys.back, "")))
+
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
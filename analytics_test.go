@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeInvocations(t *testing.T) {
+	invocations := []invocation{
+		{requestId: "a", duration: "100.00 ms", memorySize: "256 MB", maxMemoryUsed: "60 MB", initDuration: "400.50 ms"},
+		{requestId: "b", duration: "20.00 ms", memorySize: "256 MB", maxMemoryUsed: "64 MB"},
+		{requestId: "c", duration: "30.00 ms", memorySize: "256 MB", maxMemoryUsed: "250 MB"},
+		// Incomplete invocations are not measured.
+		{requestId: "d", status: invocationIncomplete},
+	}
+
+	stats := analyzeInvocations(invocations, 0)
+	want := invocationStats{
+		invocations:   3,
+		coldStarts:    1,
+		durations:     []float64{20, 30, 100},
+		initDurations: []float64{400.5},
+		memoryUsed:    []float64{60, 64, 250},
+		memorySize:    256,
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Fatalf("got %+v, want %+v", stats, want)
+	}
+
+	if got, want := durationSummary(stats.durations), "p50 30 ms • p90 100 ms • p99 100 ms • max 100 ms"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	histogram := memoryHistogram(stats, 4)
+	if len(histogram) != 10 || !strings.HasSuffix(histogram[2], "2") || !strings.HasSuffix(histogram[9], "1") {
+		t.Fatalf("unexpected histogram:\n%s", strings.Join(histogram, "\n"))
+	}
+
+	// The configured memory size takes precedence over the reported one.
+	if stats := analyzeInvocations(invocations, 1024); stats.memorySize != 1024 {
+		t.Fatalf("got memory size %v, want 1024", stats.memorySize)
+	}
+}
+
+func TestMemoryHint(t *testing.T) {
+	tests := []struct {
+		memoryUsed []float64
+		memorySize float64
+		want       string
+	}{
+		{[]float64{100, 240}, 256, "consider raising the memory size"},
+		{[]float64{80, 100}, 1024, "192 MB would suffice"},
+		{[]float64{50, 60}, 128, "the memory size fits"},
+		{[]float64{140, 150}, 256, "the memory size fits"},
+	}
+
+	for _, test := range tests {
+		stats := invocationStats{memoryUsed: test.memoryUsed, memorySize: test.memorySize}
+		if got := memoryHint(stats); !strings.Contains(got, test.want) {
+			t.Errorf("memoryHint(%v of %v MB) = %q, want %q", test.memoryUsed, test.memorySize, got, test.want)
+		}
+	}
+}
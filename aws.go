@@ -1,20 +1,121 @@
 package main
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
 	"github.com/charmbracelet/bubbles/list"
 )
 
+// lambdaAPI is the subset of the Lambda client used by the application.
+type lambdaAPI interface {
+	ListFunctions(ctx context.Context, params *lambda.ListFunctionsInput, optFns ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error)
+	GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error)
+	Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error)
+	ListVersionsByFunction(ctx context.Context, params *lambda.ListVersionsByFunctionInput, optFns ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error)
+	ListAliases(ctx context.Context, params *lambda.ListAliasesInput, optFns ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error)
+	UpdateFunctionConfiguration(ctx context.Context, params *lambda.UpdateFunctionConfigurationInput, optFns ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error)
+	ListEventSourceMappings(ctx context.Context, params *lambda.ListEventSourceMappingsInput, optFns ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error)
+	GetPolicy(ctx context.Context, params *lambda.GetPolicyInput, optFns ...func(*lambda.Options)) (*lambda.GetPolicyOutput, error)
+	GetFunctionConcurrency(ctx context.Context, params *lambda.GetFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error)
+	PutFunctionConcurrency(ctx context.Context, params *lambda.PutFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error)
+	DeleteFunctionConcurrency(ctx context.Context, params *lambda.DeleteFunctionConcurrencyInput, optFns ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error)
+	ListProvisionedConcurrencyConfigs(ctx context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput, optFns ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error)
+	PutProvisionedConcurrencyConfig(ctx context.Context, params *lambda.PutProvisionedConcurrencyConfigInput, optFns ...func(*lambda.Options)) (*lambda.PutProvisionedConcurrencyConfigOutput, error)
+	DeleteProvisionedConcurrencyConfig(ctx context.Context, params *lambda.DeleteProvisionedConcurrencyConfigInput, optFns ...func(*lambda.Options)) (*lambda.DeleteProvisionedConcurrencyConfigOutput, error)
+}
+
+// cloudwatchLogsAPI is the subset of the CloudWatch Logs client used by the
+// application.
+type cloudwatchLogsAPI interface {
+	DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	DescribeLogStreams(ctx context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error)
+	GetLogEvents(ctx context.Context, params *cloudwatchlogs.GetLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error)
+	FilterLogEvents(ctx context.Context, params *cloudwatchlogs.FilterLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error)
+	StartQuery(ctx context.Context, params *cloudwatchlogs.StartQueryInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error)
+	GetQueryResults(ctx context.Context, params *cloudwatchlogs.GetQueryResultsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error)
+}
+
+// cloudwatchMetricsAPI is the subset of the CloudWatch client used by the
+// application.
+type cloudwatchMetricsAPI interface {
+	GetMetricData(ctx context.Context, params *cloudwatch.GetMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// regionClients are the clients for a single region.
+type regionClients struct {
+	lambda     lambdaAPI
+	cloudwatch cloudwatchLogsAPI
+	metrics    cloudwatchMetricsAPI
+}
+
+// A session bundles the clients for one profile and region.
+type session struct {
+	regionClients
+	profile   string
+	region    string
+	accountId string
+	// forRegion creates the clients for a region other than the session
+	// region.
+	forRegion func(region string) regionClients
+	cache     *lambdaCache
+}
+
+// clients returns the clients for the given region. An empty region refers to
+// the region of the session.
+func (s session) clients(region string) regionClients {
+	if region == "" || region == s.region {
+		return s.regionClients
+	}
+
+	return s.forRegion(region)
+}
+
+// lambdaClient returns the Lambda client for the given region.
+func (s session) lambdaClient(region string) lambdaAPI {
+	return s.clients(region).lambda
+}
+
+// cloudwatchClient returns the CloudWatch Logs client for the given region.
+func (s session) cloudwatchClient(region string) cloudwatchLogsAPI {
+	return s.clients(region).cloudwatch
+}
+
+// metricsClient returns the CloudWatch client for the given region.
+func (s session) metricsClient(region string) cloudwatchMetricsAPI {
+	return s.clients(region).metrics
+}
+
 type lambdaInfo struct {
 	arn              string
 	name             string
+	qualifier        string
+	logGroup         string
+	version          string
+	codeSha256       string
 	description      string
 	lastModified     string
 	runtime          string
@@ -22,8 +123,101 @@ type lambdaInfo struct {
 	memory           uint32
 	ephemeralStorage uint32
 	timeout          uint32
-	envVars          [][]string
-	tags             [][]string
+	// reservedConcurrency is nil unless concurrency is reserved for the
+	// function.
+	reservedConcurrency *int32
+	// revisionId identifies the configuration the info was fetched from.
+	revisionId string
+	envVars    [][]string
+	// envIncomplete is set for cached details that lack the values of
+	// secrets.
+	envIncomplete bool
+	tags          [][]string
+}
+
+// A provisionedConcurrency is the provisioned concurrency config of a version
+// or alias.
+type provisionedConcurrency struct {
+	qualifier string
+	requested int32
+	allocated int32
+	available int32
+	status    string
+	reason    string
+}
+
+// A concurrencyInfo holds the reserved concurrency of a function, which is nil
+// if none is reserved, and its provisioned concurrency configs.
+type concurrencyInfo struct {
+	reserved    *int32
+	provisioned []provisionedConcurrency
+}
+
+// A lambdaVersion is a published version of a function, or $LATEST.
+type lambdaVersion struct {
+	version      string
+	description  string
+	codeSha256   string
+	lastModified string
+}
+
+// A lambdaAlias points to a version of a function. Invocations are routed to
+// the versions by their weights, which add up to one.
+type lambdaAlias struct {
+	name        string
+	description string
+	version     string
+	weights     map[string]float64
+}
+
+// An eventSourceMapping reads batches of records from a stream or queue and
+// invokes a function with them.
+type eventSourceMapping struct {
+	kind       string
+	source     string
+	batchSize  int32
+	state      string
+	lastResult string
+}
+
+// A policyPermission is a statement of the resource-based policy of a
+// function that allows a principal to invoke it.
+type policyPermission struct {
+	sid       string
+	principal string
+	source    string
+	action    string
+}
+
+type invokeResult struct {
+	statusCode      int32
+	functionError   string
+	executedVersion string
+	payload         string
+	log             string
+}
+
+// A query result holds the rows of a Logs Insights query. Columns are
+// ordered as projected by the query, followed by other fields in the order of
+// their first appearance in the results.
+type queryResult struct {
+	columns        []string
+	rows           [][]string
+	recordsMatched float64
+	recordsScanned float64
+}
+
+// A metricSeries holds the values of a metric for consecutive periods. Periods
+// without data points have a value of zero.
+type metricSeries struct {
+	name   string
+	unit   string
+	values []float64
+	// points are the values of the periods with data points.
+	points []float64
+	// p99 is the 99th percentile over all periods of metrics with
+	// percentiles, unless there was no data.
+	p99 *float64
 }
 
 type logStream struct {
@@ -32,7 +226,154 @@ type logStream struct {
 	expired            bool
 }
 
-func getLambdaFunctions(ctx context.Context, c *lambda.Client) ([]list.Item, error) {
+// newSession loads the shared AWS config for the given profile and region. An
+// empty profile or region falls back to the default resolution through the
+// environment and shared config files. The API options are applied to all
+// clients of the session.
+func newSession(ctx context.Context, profile string, region string, apiOptions ...func(*middleware.Stack) error) (session, error) {
+	opts := []func(*config.LoadOptions) error{config.WithAPIOptions(apiOptions)}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return session{}, err
+	}
+
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return session{}, err
+	}
+
+	// Only some credential providers know the account, e.g. static keys
+	// don't.
+	accountId := credentials.AccountID
+	if accountId == "" {
+		identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			log.Printf("[Warn] resolving the account id: %v", err)
+		} else {
+			accountId = deref(identity.Account)
+		}
+	}
+
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+
+	if profile == "" {
+		profile = "default"
+	}
+
+	return session{
+		regionClients: newRegionClients(cfg),
+		profile:       profile,
+		region:        cfg.Region,
+		accountId:     accountId,
+		forRegion: reuseRegionClients(func(region string) regionClients {
+			regional := cfg.Copy()
+			regional.Region = region
+
+			return newRegionClients(regional)
+		}),
+	}, nil
+}
+
+// reuseRegionClients returns a function that creates the clients of a region
+// on first use and returns the same clients afterwards, so that requests
+// polling other regions don't create new clients each time. It may be called
+// concurrently.
+func reuseRegionClients(create func(region string) regionClients) func(region string) regionClients {
+	var mu sync.Mutex
+	clients := map[string]regionClients{}
+
+	return func(region string) regionClients {
+		mu.Lock()
+		defer mu.Unlock()
+
+		c, ok := clients[region]
+		if !ok {
+			c = create(region)
+			clients[region] = c
+		}
+
+		return c
+	}
+}
+
+// withCallTimeout limits each call of a client to the given timeout, including
+// its retries.
+func withCallTimeout(timeout time.Duration) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CallTimeout", func(
+			ctx context.Context,
+			in middleware.InitializeInput,
+			next middleware.InitializeHandler,
+		) (middleware.InitializeOutput, middleware.Metadata, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return next.HandleInitialize(ctx, in)
+		}), middleware.Before)
+	}
+}
+
+func newRegionClients(cfg aws.Config) regionClients {
+	return regionClients{
+		lambda:     lambda.NewFromConfig(cfg),
+		cloudwatch: cloudwatchlogs.NewFromConfig(cfg),
+		metrics:    cloudwatch.NewFromConfig(cfg),
+	}
+}
+
+// listLambdaFunctions lists the functions of the session region or, if any
+// regions are given, of all those regions.
+func listLambdaFunctions(ctx context.Context, sess session, regions []string) ([]list.Item, error) {
+	if len(regions) == 0 {
+		return getLambdaFunctions(ctx, sess.lambda, "")
+	}
+
+	results := make([][]list.Item, len(regions))
+	errs := make([]error, len(regions))
+
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			results[i], errs[i] = getLambdaFunctions(ctx, sess.lambdaClient(region), region)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", region, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	functions := make([]list.Item, 0)
+	for _, result := range results {
+		functions = append(functions, result...)
+	}
+
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].(lambdaItem).name < functions[j].(lambdaItem).name
+	})
+
+	return functions, nil
+}
+
+// getLambdaFunctions lists all functions reachable through the client and
+// tags them with the given region.
+func getLambdaFunctions(ctx context.Context, c lambdaAPI, region string) ([]list.Item, error) {
 	res, err := c.ListFunctions(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -44,7 +385,7 @@ func getLambdaFunctions(ctx context.Context, c *lambda.Client) ([]list.Item, err
 
 	for {
 		for _, fn := range res.Functions {
-			functions = append(functions, lambdaItem{name: *fn.FunctionName, logGroup: *fn.LoggingConfig.LogGroup})
+			functions = append(functions, lambdaItem{name: *fn.FunctionName, logGroup: *fn.LoggingConfig.LogGroup, region: region})
 		}
 
 		if res.NextMarker == nil {
@@ -60,10 +401,15 @@ func getLambdaFunctions(ctx context.Context, c *lambda.Client) ([]list.Item, err
 	return functions, nil
 }
 
-func getLambdaInfo(ctx context.Context, c *lambda.Client, name string) (lambdaInfo, error) {
-	res, err := c.GetFunction(ctx, &lambda.GetFunctionInput{
-		FunctionName: &name,
-	})
+// getLambdaInfo fetches the configuration of a function. The qualifier may
+// name a version or alias and is ignored if empty.
+func getLambdaInfo(ctx context.Context, c lambdaAPI, name string, qualifier string) (lambdaInfo, error) {
+	input := &lambda.GetFunctionInput{FunctionName: &name}
+	if qualifier != "" {
+		input.Qualifier = &qualifier
+	}
+
+	res, err := c.GetFunction(ctx, input)
 	if err != nil {
 		return lambdaInfo{}, err
 	}
@@ -75,6 +421,9 @@ func getLambdaInfo(ctx context.Context, c *lambda.Client, name string) (lambdaIn
 	fnInfo := lambdaInfo{
 		arn:              "invalid arn",
 		name:             name,
+		qualifier:        qualifier,
+		version:          "invalid version",
+		codeSha256:       "invalid code sha",
 		description:      "invalid description",
 		lastModified:     "invalid last modified date",
 		runtime:          "invalid runtime",
@@ -98,6 +447,18 @@ func getLambdaInfo(ctx context.Context, c *lambda.Client, name string) (lambdaIn
 		fnInfo.description = *res.Configuration.Description
 	}
 
+	if res.Configuration.Version != nil {
+		fnInfo.version = *res.Configuration.Version
+	}
+
+	if res.Configuration.LoggingConfig != nil && res.Configuration.LoggingConfig.LogGroup != nil {
+		fnInfo.logGroup = *res.Configuration.LoggingConfig.LogGroup
+	}
+
+	if res.Configuration.CodeSha256 != nil {
+		fnInfo.codeSha256 = *res.Configuration.CodeSha256
+	}
+
 	if res.Configuration.MemorySize != nil {
 		fnInfo.memory = uint32(*res.Configuration.MemorySize)
 	}
@@ -115,31 +476,474 @@ func getLambdaInfo(ctx context.Context, c *lambda.Client, name string) (lambdaIn
 	}
 
 	fnInfo.runtime = string(res.Configuration.Runtime)
+	fnInfo.revisionId = deref(res.Configuration.RevisionId)
 
-	fnInfo.tags = make([][]string, 0, len(res.Tags))
-	for k, v := range res.Tags {
-		fnInfo.tags = append(fnInfo.tags, []string{k, v})
+	if res.Concurrency != nil {
+		fnInfo.reservedConcurrency = res.Concurrency.ReservedConcurrentExecutions
 	}
 
+	fnInfo.tags = sortedRows(res.Tags)
+
 	if res.Configuration.Environment == nil {
 		return fnInfo, nil
 	}
 
-	fnInfo.envVars = make([][]string, 0, len(res.Configuration.Environment.Variables))
-	for k, v := range res.Configuration.Environment.Variables {
-		fnInfo.envVars = append(fnInfo.envVars, []string{k, v})
-	}
+	fnInfo.envVars = sortedRows(res.Configuration.Environment.Variables)
 
 	return fnInfo, nil
 }
 
-func getLogStreams(ctx context.Context, c *cloudwatchlogs.Client, logGroup string) ([]logStream, error) {
+// functionUpdatePollInterval is the delay between polls for the status of a
+// function configuration update.
+var functionUpdatePollInterval = time.Second
+
+// updateEnvVars replaces the environment variables of a function and waits
+// for the update to complete. The update fails if the configuration no longer
+// has the given revision, unless it is empty.
+func updateEnvVars(ctx context.Context, c lambdaAPI, name string, revisionId string, vars map[string]string) error {
+	input := &lambda.UpdateFunctionConfigurationInput{
+		FunctionName: &name,
+		Environment:  &lambdatypes.Environment{Variables: vars},
+	}
+	if revisionId != "" {
+		input.RevisionId = &revisionId
+	}
+
+	res, err := c.UpdateFunctionConfiguration(ctx, input)
+	var preconditionErr *lambdatypes.PreconditionFailedException
+	if errors.As(err, &preconditionErr) {
+		return fmt.Errorf("the function has been changed since its details were loaded, reload them and try again")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	status, reason := res.LastUpdateStatus, res.LastUpdateStatusReason
+	for status == lambdatypes.LastUpdateStatusInProgress {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(functionUpdatePollInterval):
+		}
+
+		fn, err := c.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: &name})
+		if err != nil {
+			return err
+		}
+
+		if fn.Configuration == nil {
+			return fmt.Errorf("received nil function config")
+		}
+
+		status, reason = fn.Configuration.LastUpdateStatus, fn.Configuration.LastUpdateStatusReason
+	}
+
+	if status == lambdatypes.LastUpdateStatusFailed {
+		return fmt.Errorf("update failed: %s", deref(reason))
+	}
+
+	return nil
+}
+
+// getLambdaVersions fetches all versions and aliases of a function.
+func getLambdaVersions(ctx context.Context, c lambdaAPI, name string) ([]lambdaVersion, []lambdaAlias, error) {
+	versions := make([]lambdaVersion, 0)
+	versionsInput := &lambda.ListVersionsByFunctionInput{FunctionName: &name}
+
+	for {
+		res, err := c.ListVersionsByFunction(ctx, versionsInput)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, v := range res.Versions {
+			versions = append(versions, lambdaVersion{
+				version:      deref(v.Version),
+				description:  deref(v.Description),
+				codeSha256:   deref(v.CodeSha256),
+				lastModified: deref(v.LastModified),
+			})
+		}
+
+		if res.NextMarker == nil {
+			break
+		}
+
+		versionsInput.Marker = res.NextMarker
+	}
+
+	aliases := make([]lambdaAlias, 0)
+	aliasesInput := &lambda.ListAliasesInput{FunctionName: &name}
+
+	for {
+		res, err := c.ListAliases(ctx, aliasesInput)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, a := range res.Aliases {
+			alias := lambdaAlias{
+				name:        deref(a.Name),
+				description: deref(a.Description),
+				version:     deref(a.FunctionVersion),
+				weights:     map[string]float64{},
+			}
+
+			primary := 1.0
+			if a.RoutingConfig != nil {
+				for version, weight := range a.RoutingConfig.AdditionalVersionWeights {
+					alias.weights[version] = weight
+					primary -= weight
+				}
+			}
+			alias.weights[alias.version] = primary
+
+			aliases = append(aliases, alias)
+		}
+
+		if res.NextMarker == nil {
+			break
+		}
+
+		aliasesInput.Marker = res.NextMarker
+	}
+
+	return versions, aliases, nil
+}
+
+// getConcurrency fetches the reserved concurrency of a function and the
+// provisioned concurrency configs of its versions and aliases.
+func getConcurrency(ctx context.Context, c lambdaAPI, name string) (concurrencyInfo, error) {
+	res, err := c.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{FunctionName: &name})
+	if err != nil {
+		return concurrencyInfo{}, err
+	}
+
+	info := concurrencyInfo{reserved: res.ReservedConcurrentExecutions, provisioned: make([]provisionedConcurrency, 0)}
+	input := &lambda.ListProvisionedConcurrencyConfigsInput{FunctionName: &name}
+
+	for {
+		res, err := c.ListProvisionedConcurrencyConfigs(ctx, input)
+		if err != nil {
+			return concurrencyInfo{}, err
+		}
+
+		for _, config := range res.ProvisionedConcurrencyConfigs {
+			// The function ARN is qualified with the version or alias.
+			arn := deref(config.FunctionArn)
+
+			info.provisioned = append(info.provisioned, provisionedConcurrency{
+				qualifier: arn[strings.LastIndex(arn, ":")+1:],
+				requested: deref(config.RequestedProvisionedConcurrentExecutions),
+				allocated: deref(config.AllocatedProvisionedConcurrentExecutions),
+				available: deref(config.AvailableProvisionedConcurrentExecutions),
+				status:    string(config.Status),
+				reason:    deref(config.StatusReason),
+			})
+		}
+
+		if res.NextMarker == nil {
+			break
+		}
+
+		input.Marker = res.NextMarker
+	}
+
+	slices.SortFunc(info.provisioned, func(a, b provisionedConcurrency) int {
+		return strings.Compare(a.qualifier, b.qualifier)
+	})
+
+	return info, nil
+}
+
+// setReservedConcurrency reserves concurrency for a function or, if value is
+// nil, removes the reservation.
+func setReservedConcurrency(ctx context.Context, c lambdaAPI, name string, value *int32) error {
+	if value == nil {
+		_, err := c.DeleteFunctionConcurrency(ctx, &lambda.DeleteFunctionConcurrencyInput{FunctionName: &name})
+
+		return err
+	}
+
+	_, err := c.PutFunctionConcurrency(ctx, &lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 &name,
+		ReservedConcurrentExecutions: value,
+	})
+
+	return err
+}
+
+// setProvisionedConcurrency provisions concurrency for a version or alias of a
+// function or, if value is nil, removes its provisioned concurrency config.
+func setProvisionedConcurrency(ctx context.Context, c lambdaAPI, name string, qualifier string, value *int32) error {
+	if value == nil {
+		_, err := c.DeleteProvisionedConcurrencyConfig(ctx, &lambda.DeleteProvisionedConcurrencyConfigInput{
+			FunctionName: &name,
+			Qualifier:    &qualifier,
+		})
+
+		return err
+	}
+
+	_, err := c.PutProvisionedConcurrencyConfig(ctx, &lambda.PutProvisionedConcurrencyConfigInput{
+		FunctionName:                    &name,
+		Qualifier:                       &qualifier,
+		ProvisionedConcurrentExecutions: value,
+	})
+
+	return err
+}
+
+// getLambdaTriggers fetches the event source mappings and the resource-based
+// policy of a function. The qualifier may name a version or alias and is
+// ignored if empty.
+func getLambdaTriggers(ctx context.Context, c lambdaAPI, name string, qualifier string) ([]eventSourceMapping, []policyPermission, error) {
+	functionName := name
+	if qualifier != "" {
+		functionName += ":" + qualifier
+	}
+
+	mappings := make([]eventSourceMapping, 0)
+	mappingsInput := &lambda.ListEventSourceMappingsInput{FunctionName: &functionName}
+
+	for {
+		res, err := c.ListEventSourceMappings(ctx, mappingsInput)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, mapping := range res.EventSourceMappings {
+			kind, source := eventSourceKind(mapping)
+			mappings = append(mappings, eventSourceMapping{
+				kind:       kind,
+				source:     source,
+				batchSize:  deref(mapping.BatchSize),
+				state:      deref(mapping.State),
+				lastResult: deref(mapping.LastProcessingResult),
+			})
+		}
+
+		if res.NextMarker == nil {
+			break
+		}
+
+		mappingsInput.Marker = res.NextMarker
+	}
+
+	policyInput := &lambda.GetPolicyInput{FunctionName: &name}
+	if qualifier != "" {
+		policyInput.Qualifier = &qualifier
+	}
+
+	res, err := c.GetPolicy(ctx, policyInput)
+
+	// Functions without a resource-based policy have no permissions.
+	var notFound *lambdatypes.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return mappings, []policyPermission{}, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	permissions, err := parsePolicy(deref(res.Policy))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing policy: %w", err)
+	}
+
+	return mappings, permissions, nil
+}
+
+// eventSourceKinds name the services of event source ARNs.
+var eventSourceKinds = map[string]string{
+	"sqs":      "SQS",
+	"kinesis":  "Kinesis",
+	"dynamodb": "DynamoDB",
+	"kafka":    "Kafka (MSK)",
+	"mq":       "MQ",
+	"rds":      "DocumentDB",
+}
+
+// eventSourceKind returns the type of the source of an event source mapping
+// and the name of the source.
+func eventSourceKind(mapping lambdatypes.EventSourceMappingConfiguration) (string, string) {
+	if mapping.SelfManagedEventSource != nil {
+		return "Kafka", strings.Join(mapping.SelfManagedEventSource.Endpoints["KAFKA_BOOTSTRAP_SERVERS"], ",")
+	}
+
+	// ARNs are of the form arn:partition:service:region:account:resource.
+	parts := strings.SplitN(deref(mapping.EventSourceArn), ":", 6)
+	if len(parts) < 6 {
+		return "unknown", deref(mapping.EventSourceArn)
+	}
+
+	kind, ok := eventSourceKinds[parts[2]]
+	if !ok {
+		kind = parts[2]
+	}
+
+	return kind, parts[5]
+}
+
+// servicePrincipals name the services of common service principals.
+var servicePrincipals = map[string]string{
+	"apigateway.amazonaws.com":           "API Gateway",
+	"s3.amazonaws.com":                   "S3",
+	"events.amazonaws.com":               "EventBridge",
+	"scheduler.amazonaws.com":            "EventBridge Scheduler",
+	"sns.amazonaws.com":                  "SNS",
+	"logs.amazonaws.com":                 "CloudWatch Logs",
+	"elasticloadbalancing.amazonaws.com": "Application Load Balancer",
+	"iot.amazonaws.com":                  "IoT",
+	"cognito-idp.amazonaws.com":          "Cognito",
+	"secretsmanager.amazonaws.com":       "Secrets Manager",
+}
+
+// A policyDocument is the JSON representation of a resource-based policy.
+// Principals, actions and condition values may be a string or a list.
+type policyDocument struct {
+	Statement []struct {
+		Sid       string
+		Effect    string
+		Principal json.RawMessage
+		Action    json.RawMessage
+		Condition map[string]map[string]json.RawMessage
+	}
+}
+
+// parsePolicy returns the statements of a resource-based policy that allow
+// invocations.
+func parsePolicy(policy string) ([]policyPermission, error) {
+	permissions := make([]policyPermission, 0)
+	if policy == "" {
+		return permissions, nil
+	}
+
+	var doc policyDocument
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return nil, err
+	}
+
+	for _, statement := range doc.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		principals := policyStrings(statement.Principal)
+		var principal map[string]json.RawMessage
+		if json.Unmarshal(statement.Principal, &principal) == nil {
+			principals = nil
+			for _, key := range slices.Sorted(maps.Keys(principal)) {
+				principals = append(principals, policyStrings(principal[key])...)
+			}
+		}
+
+		for i, p := range principals {
+			if name, ok := servicePrincipals[p]; ok {
+				principals[i] = name
+			}
+		}
+
+		// Condition keys are case-insensitive and nested in operators
+		// such as ArnLike.
+		conditions := map[string]string{}
+		for _, operator := range statement.Condition {
+			for key, value := range operator {
+				conditions[strings.ToLower(key)] = strings.Join(policyStrings(value), ", ")
+			}
+		}
+
+		source := ""
+		switch {
+		case conditions["aws:sourcearn"] != "":
+			source = conditions["aws:sourcearn"]
+		case conditions["lambda:functionurlauthtype"] != "":
+			source = "function URL, auth " + conditions["lambda:functionurlauthtype"]
+		case conditions["aws:sourceaccount"] != "":
+			source = "account " + conditions["aws:sourceaccount"]
+		}
+
+		permissions = append(permissions, policyPermission{
+			sid:       statement.Sid,
+			principal: strings.Join(principals, ", "),
+			source:    source,
+			action:    strings.Join(policyStrings(statement.Action), ", "),
+		})
+	}
+
+	return permissions, nil
+}
+
+// policyStrings decodes a policy value that is either a string or a list of
+// strings.
+func policyStrings(raw json.RawMessage) []string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []string{s}
+	}
+
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+
+	return nil
+}
+
+func invokeLambda(ctx context.Context, c lambdaAPI, name string, payload string) (invokeResult, error) {
+	res, err := c.Invoke(ctx, &lambda.InvokeInput{
+		FunctionName: &name,
+		LogType:      lambdatypes.LogTypeTail,
+		Payload:      []byte(payload),
+	})
+	if err != nil {
+		return invokeResult{}, err
+	}
+
+	result := invokeResult{
+		statusCode: res.StatusCode,
+		payload:    string(res.Payload),
+	}
+
+	if res.FunctionError != nil {
+		result.functionError = *res.FunctionError
+	}
+
+	if res.ExecutedVersion != nil {
+		result.executedVersion = *res.ExecutedVersion
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, res.Payload, "", "  "); err == nil {
+		result.payload = indented.String()
+	}
+
+	if res.LogResult != nil {
+		logResult, err := base64.StdEncoding.DecodeString(*res.LogResult)
+		if err != nil {
+			return invokeResult{}, fmt.Errorf("could not decode log result: %w", err)
+		}
+
+		result.log = strings.TrimSuffix(string(logResult), "\n")
+	}
+
+	return result, nil
+}
+
+// getLogStreams fetches a page of the streams of a log group, most recently
+// active first. An empty token fetches the first page; the returned token is
+// empty once all streams have been fetched. If versions are given, only the
+// streams of these function versions are returned.
+func getLogStreams(ctx context.Context, c cloudwatchLogsAPI, logGroup string, nextToken string, versions []string) ([]logStream, string, error) {
 	logGroupRes, err := c.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
 		LogGroupNamePrefix: &logGroup,
 	})
+	if err != nil {
+		return nil, "", err
+	}
 
 	if len(logGroupRes.LogGroups) == 0 {
-		return nil, fmt.Errorf("log group not found")
+		return nil, "", fmt.Errorf("log group not found")
 	}
 
 	retention := int64(-1)
@@ -147,96 +951,486 @@ func getLogStreams(ctx context.Context, c *cloudwatchlogs.Client, logGroup strin
 		retention = int64(*logGroupRes.LogGroups[0].RetentionInDays) * 86400000
 	}
 
-	res, err := c.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
+	input := &cloudwatchlogs.DescribeLogStreamsInput{
 		LogGroupName: &logGroup,
 		Descending:   ptr(true),
 		OrderBy:      types.OrderByLastEventTime,
-	})
-	if err != nil {
-		return nil, err
+	}
+	if nextToken != "" {
+		input.NextToken = &nextToken
 	}
 
-	streams := make([]logStream, 0, len(res.LogStreams))
-	requests := 1
-	maxRequests := 10
+	streams := make([]logStream, 0)
 
 	for {
+		res, err := c.DescribeLogStreams(ctx, input)
+		if err != nil {
+			return nil, "", err
+		}
+
 		for _, stream := range res.LogStreams {
+			if len(versions) > 0 && !slices.Contains(versions, logStreamVersion(*stream.LogStreamName)) {
+				continue
+			}
+
+			// Streams without events have no last event timestamp.
 			expired := false
+			lastEventTimestamp := "-"
 			if stream.LastEventTimestamp != nil {
-				expired = time.UnixMilli(*stream.LastEventTimestamp + retention).Before(time.Now())
+				expired = retention >= 0 && time.UnixMilli(*stream.LastEventTimestamp+retention).Before(time.Now())
+				lastEventTimestamp = time.Unix(*stream.LastEventTimestamp/1000, 0).Format(time.RFC1123)
 			}
 
 			streams = append(streams, logStream{
 				name:               *stream.LogStreamName,
-				lastEventTimestamp: time.Unix(*stream.LastEventTimestamp/1000, 0).Format(time.RFC1123),
+				lastEventTimestamp: lastEventTimestamp,
 				expired:            expired,
 			})
 		}
 
-		if res.NextToken == nil || requests == maxRequests {
-			break
+		if res.NextToken == nil {
+			return streams, "", nil
 		}
 
-		res, err = c.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
-			LogGroupName: &logGroup,
-			Descending:   ptr(true),
-			NextToken:    res.NextToken,
-			OrderBy:      types.OrderByLastEventTime,
-		})
-		if err != nil {
-			return nil, err
+		// Pages without streams of the requested versions are skipped.
+		if len(streams) > 0 {
+			return streams, *res.NextToken, nil
 		}
 
-		requests++
+		input.NextToken = res.NextToken
 	}
+}
+
+// logStreamVersion returns the function version of a Lambda log stream, whose
+// names are of the form "2024/09/01/[$LATEST]<id>".
+func logStreamVersion(name string) string {
+	_, rest, ok := strings.Cut(name, "[")
+	if !ok {
+		return ""
+	}
+
+	version, _, _ := strings.Cut(rest, "]")
 
-	return streams, nil
+	return version
 }
 
-func getLogEvents(ctx context.Context, c *cloudwatchlogs.Client, logGroup string, logStream string) ([][]string, error) {
-	res, err := c.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
-		LogGroupName:  &logGroup,
-		LogStreamName: &logStream,
-	})
-	if err != nil {
-		return nil, err
+// A logEventPage holds events of a log stream in chronological order along
+// with the tokens to fetch newer and older events.
+type logEventPage struct {
+	events       [][]string
+	forwardToken string
+	// backwardToken is empty once the start of the stream has been reached.
+	backwardToken string
+}
+
+// getLogEvents fetches a page of events of a log stream. An empty backward
+// token fetches the most recent events, otherwise the events preceding the
+// token are fetched. Events before start are omitted unless start is zero. The
+// forward token of the page can be passed to getNewLogEvents to fetch events
+// that are ingested afterwards.
+func getLogEvents(ctx context.Context, c cloudwatchLogsAPI, logGroup string, logStream string, backwardToken string, start time.Time) (logEventPage, error) {
+	page := logEventPage{events: make([][]string, 0)}
+
+	for {
+		input := &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  &logGroup,
+			LogStreamName: &logStream,
+		}
+		if backwardToken != "" {
+			input.NextToken = &backwardToken
+		}
+
+		if !start.IsZero() {
+			input.StartTime = ptr(start.UnixMilli())
+		}
+
+		res, err := c.GetLogEvents(ctx, input)
+		if err != nil {
+			return logEventPage{}, err
+		}
+
+		if page.forwardToken == "" && res.NextForwardToken != nil {
+			page.forwardToken = *res.NextForwardToken
+		}
+
+		for _, event := range res.Events {
+			if event.Message == nil {
+				continue
+			}
+
+			page.events = append(page.events, logEventRow(event))
+		}
+
+		// The start of the stream has been reached once the same backward
+		// token is returned again.
+		if res.NextBackwardToken == nil || (backwardToken != "" && *res.NextBackwardToken == backwardToken) {
+			page.backwardToken = ""
+
+			return page, nil
+		}
+
+		page.backwardToken = *res.NextBackwardToken
+
+		// Pages may be empty even though older events exist.
+		if len(page.events) > 0 {
+			return page, nil
+		}
+
+		backwardToken = page.backwardToken
 	}
+}
 
-	requests := 1
-	maxRequests := 5
-	logEvents := make([][]string, 0, 100)
+// getNewLogEvents fetches all events of a log stream following the given
+// forward token and returns them along with the token to continue from.
+func getNewLogEvents(ctx context.Context, c cloudwatchLogsAPI, logGroup string, logStream string, token string) ([][]string, string, error) {
+	logEvents := make([][]string, 0)
 
 	for {
+		res, err := c.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  &logGroup,
+			LogStreamName: &logStream,
+			NextToken:     &token,
+			StartFromHead: ptr(true),
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
 		for _, event := range res.Events {
 			if event.Message == nil {
 				continue
 			}
 
-			logEvents = append(logEvents, []string{
-				time.Unix(*event.Timestamp/1000, 0).Format(time.RFC1123),
-				strings.TrimSuffix(*event.Message, "\n"),
-			})
+			logEvents = append(logEvents, logEventRow(event))
 		}
 
-		if res.NextBackwardToken == nil || requests == maxRequests {
+		// The end of the stream is reached once the same token is returned.
+		if res.NextForwardToken == nil || *res.NextForwardToken == token {
 			break
 		}
 
-		res, err = c.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
+		token = *res.NextForwardToken
+	}
+
+	return logEvents, token, nil
+}
+
+// forEachLogEvent calls fn for every event of a log stream, starting with the
+// oldest one.
+func forEachLogEvent(ctx context.Context, c cloudwatchLogsAPI, logGroup string, logStream string, fn func(types.OutputLogEvent) error) error {
+	var token *string
+
+	for {
+		res, err := c.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
 			LogGroupName:  &logGroup,
 			LogStreamName: &logStream,
-			NextToken:     res.NextBackwardToken,
+			NextToken:     token,
+			StartFromHead: ptr(true),
 		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range res.Events {
+			if event.Message == nil {
+				continue
+			}
+
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		// The end of the stream is reached once the same token is returned.
+		if res.NextForwardToken == nil || (token != nil && *res.NextForwardToken == *token) {
+			return nil
+		}
+
+		token = res.NextForwardToken
+	}
+}
+
+// filterLogEvents fetches the events of all streams of a log group between
+// start and end that match the filter pattern, which matches all events if
+// empty. The events are returned as rows of timestamp, stream and message in
+// chronological order. Only the newest limit events are kept; truncated
+// reports whether older events were dropped.
+func filterLogEvents(ctx context.Context, c cloudwatchLogsAPI, logGroup string, pattern string, start time.Time, end time.Time, limit int) ([][]string, bool, error) {
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: &logGroup,
+		StartTime:    ptr(start.UnixMilli()),
+		EndTime:      ptr(end.UnixMilli()),
+	}
+	if pattern != "" {
+		input.FilterPattern = &pattern
+	}
+
+	events := make([]types.FilteredLogEvent, 0)
+	truncated := false
+
+	// Pages hold events of several streams, which are merged by timestamp.
+	// Any page may hold newer events than the previous ones, so all pages are
+	// fetched while only the newest events are kept.
+	for {
+		res, err := c.FilterLogEvents(ctx, input)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, event := range res.Events {
+			if event.Message != nil && event.Timestamp != nil {
+				events = append(events, event)
+			}
+		}
+
+		slices.SortStableFunc(events, func(a, b types.FilteredLogEvent) int {
+			return cmp.Compare(*a.Timestamp, *b.Timestamp)
+		})
+
+		if len(events) > limit {
+			events = slices.Delete(events, 0, len(events)-limit)
+			truncated = true
+		}
+
+		if res.NextToken == nil {
+			break
+		}
+
+		input.NextToken = res.NextToken
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, event := range events {
+		rows = append(rows, []string{
+			time.Unix(*event.Timestamp/1000, 0).Format(time.RFC1123),
+			deref(event.LogStreamName),
+			strings.TrimSuffix(*event.Message, "\n"),
+		})
+	}
+
+	return rows, truncated, nil
+}
+
+func logEventRow(event types.OutputLogEvent) []string {
+	return []string{
+		time.Unix(*event.Timestamp/1000, 0).Format(time.RFC1123),
+		strings.TrimSuffix(*event.Message, "\n"),
+	}
+}
+
+// lambdaMetrics are the metrics of a function shown in the metrics view, along
+// with the statistic they are aggregated by.
+var lambdaMetrics = []struct {
+	name string
+	stat string
+	unit string
+	// percentiles is set for metrics of individual invocations, whose 99th
+	// percentile is queried over the whole time range.
+	percentiles bool
+}{
+	{"Invocations", "Sum", "", false},
+	{"Errors", "Sum", "", false},
+	{"Duration", "Average", "ms", true},
+	{"Throttles", "Sum", "", false},
+	{"ConcurrentExecutions", "Maximum", "", false},
+}
+
+// getLambdaMetrics fetches the metrics of a function between start and end,
+// aggregated over periods of the given length.
+func getLambdaMetrics(ctx context.Context, c cloudwatchMetricsAPI, name string, start time.Time, end time.Time, period time.Duration) ([]metricSeries, error) {
+	query := func(id string, metric string, stat string, period time.Duration) cloudwatchtypes.MetricDataQuery {
+		return cloudwatchtypes.MetricDataQuery{
+			Id: &id,
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  ptr("AWS/Lambda"),
+					MetricName: &metric,
+					Dimensions: []cloudwatchtypes.Dimension{{Name: ptr("FunctionName"), Value: &name}},
+				},
+				Period: ptr(int32(period.Seconds())),
+				Stat:   &stat,
+			},
+		}
+	}
+
+	// Percentiles of the averages of periods are not those of invocations,
+	// so they are queried over a single period spanning the whole range,
+	// which must be a multiple of a minute.
+	window := end.Sub(start).Truncate(time.Minute)
+	if window < end.Sub(start) {
+		window += time.Minute
+	}
+
+	queries := make([]cloudwatchtypes.MetricDataQuery, 0, len(lambdaMetrics))
+	for i, metric := range lambdaMetrics {
+		queries = append(queries, query(fmt.Sprintf("m%d", i), metric.name, metric.stat, period))
+
+		if metric.percentiles {
+			queries = append(queries, query(fmt.Sprintf("p%d", i), metric.name, "p99", window))
+		}
+	}
+
+	series := make([]metricSeries, len(lambdaMetrics))
+	for i, metric := range lambdaMetrics {
+		series[i] = metricSeries{
+			name:   metric.name,
+			unit:   metric.unit,
+			values: make([]float64, int(end.Sub(start)/period)),
+		}
+	}
+
+	input := &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         &start,
+		EndTime:           &end,
+		ScanBy:            cloudwatchtypes.ScanByTimestampAscending,
+	}
+
+	for {
+		res, err := c.GetMetricData(ctx, input)
 		if err != nil {
 			return nil, err
 		}
-		requests++
+
+		for _, result := range res.MetricDataResults {
+			var kind rune
+			var i int
+			if _, err := fmt.Sscanf(deref(result.Id), "%c%d", &kind, &i); err != nil || i >= len(series) {
+				continue
+			}
+
+			if kind == 'p' {
+				if len(result.Values) > 0 {
+					series[i].p99 = &result.Values[0]
+				}
+
+				continue
+			}
+
+			for j, timestamp := range result.Timestamps {
+				bucket := int(timestamp.Sub(start) / period)
+				if bucket >= 0 && bucket < len(series[i].values) && j < len(result.Values) {
+					series[i].values[bucket] = result.Values[j]
+					series[i].points = append(series[i].points, result.Values[j])
+				}
+			}
+		}
+
+		if res.NextToken == nil {
+			return series, nil
+		}
+
+		input.NextToken = res.NextToken
+	}
+}
+
+// queryPollInterval is the delay between polls for Logs Insights results.
+var queryPollInterval = time.Second
+
+// runInsightsQuery starts a Logs Insights query and polls for its results
+// until the query has finished.
+func runInsightsQuery(ctx context.Context, c cloudwatchLogsAPI, logGroup string, query string, start time.Time, end time.Time) (queryResult, error) {
+	startRes, err := c.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		LogGroupName: &logGroup,
+		QueryString:  &query,
+		StartTime:    ptr(start.Unix()),
+		EndTime:      ptr(end.Unix()),
+	})
+	if err != nil {
+		return queryResult{}, err
 	}
 
-	return logEvents, nil
+	for {
+		res, err := c.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: startRes.QueryId,
+		})
+		if err != nil {
+			return queryResult{}, err
+		}
+
+		switch res.Status {
+		case types.QueryStatusComplete:
+			return newQueryResult(query, res), nil
+		case types.QueryStatusFailed, types.QueryStatusCancelled, types.QueryStatusTimeout:
+			return queryResult{}, fmt.Errorf("query finished with status %s", res.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return queryResult{}, ctx.Err()
+		case <-time.After(queryPollInterval):
+		}
+	}
 }
 
-func ptr[T ~string | ~float64 | ~float32 | ~int | ~uint | ~bool](val T) *T {
+func newQueryResult(query string, res *cloudwatchlogs.GetQueryResultsOutput) queryResult {
+	result := queryResult{
+		columns: queryColumns(query),
+		rows:    make([][]string, 0, len(res.Results)),
+	}
+
+	if res.Statistics != nil {
+		result.recordsMatched = res.Statistics.RecordsMatched
+		result.recordsScanned = res.Statistics.RecordsScanned
+	}
+
+	columnIndex := map[string]int{}
+	for i, column := range result.columns {
+		columnIndex[column] = i
+	}
+
+	for _, fields := range res.Results {
+		for _, field := range fields {
+			// @ptr references the log record and is of no use for display
+			if field.Field == nil || *field.Field == "@ptr" {
+				continue
+			}
+
+			if _, ok := columnIndex[*field.Field]; !ok {
+				columnIndex[*field.Field] = len(result.columns)
+				result.columns = append(result.columns, *field.Field)
+			}
+		}
+	}
+
+	for _, fields := range res.Results {
+		row := make([]string, len(result.columns))
+		for _, field := range fields {
+			if field.Field == nil || field.Value == nil {
+				continue
+			}
+
+			if i, ok := columnIndex[*field.Field]; ok {
+				row[i] = strings.TrimSuffix(*field.Value, "\n")
+			}
+		}
+
+		result.rows = append(result.rows, row)
+	}
+
+	return result
+}
+
+func ptr[T ~string | ~float64 | ~float32 | ~int | ~int32 | ~int64 | ~uint | ~bool](val T) *T {
 	return &val
 }
+
+// sortedRows returns the entries of m as key value rows, ordered by key.
+func sortedRows(m map[string]string) [][]string {
+	rows := make([][]string, 0, len(m))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		rows = append(rows, []string{k, m[k]})
+	}
+
+	return rows
+}
+
+// deref returns the value p points to, or the zero value if p is nil.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+
+	return *p
+}
@@ -0,0 +1,601 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultCacheTTL is the age after which cached entries are refreshed unless
+// configured otherwise.
+const defaultCacheTTL = 15 * time.Minute
+
+// A lambda list refresh request lists the functions like a lambdaListReq, but
+// in the background while the cached list is shown.
+type lambdaListRefreshReq struct {
+	regions []string
+}
+
+// Refresh messages name the account and region of the session they were
+// fetched with, which may have been switched in the meantime.
+type lambdaListRefreshMsg struct {
+	account string
+	region  string
+	regions []string
+	items   []list.Item
+}
+
+// A lambda detail refresh request fetches the details of a function in the
+// background while the cached details are shown.
+type lambdaDetailRefreshReq struct {
+	region string
+	name   string
+}
+
+type lambdaDetailRefreshMsg struct {
+	account string
+	region  string
+	info    lambdaInfo
+}
+
+// Refresh failed messages report a failed refresh, after which the cached data
+// remains shown.
+type lambdaListRefreshFailedMsg struct {
+	account string
+	region  string
+	regions []string
+	err     error
+}
+
+type lambdaDetailRefreshFailedMsg struct {
+	account string
+	region  string
+	name    string
+	err     error
+}
+
+// A lambdaCache holds function lists and details per account and region.
+// Entries are persisted to dir if it is not empty. All methods may be called
+// on a nil cache, which caches nothing.
+type lambdaCache struct {
+	mu  sync.Mutex
+	dir string
+	ttl time.Duration
+	// secrets masks the environment variables whose values are kept in
+	// memory only.
+	secrets envMasker
+	entries map[string]*regionCache
+}
+
+// A regionCache holds the cached data of an account and region. It is
+// persisted as JSON.
+type regionCache struct {
+	Functions        []cachedFunction         `json:"functions"`
+	FunctionsUpdated time.Time                `json:"functionsUpdated"`
+	Details          map[string]cachedDetails `json:"details"`
+}
+
+type cachedFunction struct {
+	Name     string `json:"name"`
+	LogGroup string `json:"logGroup"`
+}
+
+type cachedDetails struct {
+	Updated             time.Time  `json:"updated"`
+	ARN                 string     `json:"arn"`
+	LogGroup            string     `json:"logGroup"`
+	Version             string     `json:"version"`
+	CodeSha256          string     `json:"codeSha256"`
+	Description         string     `json:"description"`
+	LastModified        string     `json:"lastModified"`
+	Runtime             string     `json:"runtime"`
+	Architecture        string     `json:"architecture"`
+	Memory              uint32     `json:"memory"`
+	EphemeralStorage    uint32     `json:"ephemeralStorage"`
+	Timeout             uint32     `json:"timeout"`
+	ReservedConcurrency *int32     `json:"reservedConcurrency,omitempty"`
+	RevisionId          string     `json:"revisionId"`
+	EnvVars             [][]string `json:"envVars"`
+	// Redacted are the environment variables whose values were not
+	// persisted.
+	Redacted []string   `json:"redacted,omitempty"`
+	Tags     [][]string `json:"tags"`
+}
+
+// newLambdaCache returns a cache whose entries are refreshed once they are
+// older than ttl. An empty dir keeps the cache in memory. The values of
+// environment variables masked by secrets are not persisted.
+func newLambdaCache(dir string, ttl time.Duration, secrets envMasker) *lambdaCache {
+	return &lambdaCache{dir: dir, ttl: ttl, secrets: secrets, entries: map[string]*regionCache{}}
+}
+
+// cacheAccount returns the account the cache entries of the session are
+// stored under. Accounts whose id is unknown are told apart by their profile.
+func (s session) cacheAccount() string {
+	return accountCacheKey(s.accountId, s.profile)
+}
+
+// cacheAccount returns the account the cache entries of the session are
+// stored under.
+func (m model) cacheAccount() string {
+	return accountCacheKey(m.accountId, m.profile)
+}
+
+func accountCacheKey(accountId string, profile string) string {
+	if accountId == "" {
+		return "profile-" + profile
+	}
+
+	return accountId
+}
+
+// defaultCacheDir returns the directory the cache is persisted to by default.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Printf("[Warn] not persisting the cache: %v", err)
+
+		return ""
+	}
+
+	return filepath.Join(dir, "lambda-tui")
+}
+
+// stale reports whether an entry updated at the given time is to be
+// refreshed.
+func (c *lambdaCache) stale(updated time.Time) bool {
+	if c == nil {
+		return true
+	}
+
+	return time.Since(updated) > c.ttl
+}
+
+func (c *lambdaCache) path(accountId string, region string) string {
+	return filepath.Join(c.dir, accountId, region+".json")
+}
+
+// entry returns the cached data of an account and region, reading it from
+// disk on first access. c.mu must be held.
+func (c *lambdaCache) entry(accountId string, region string) *regionCache {
+	key := accountId + "/" + region
+	if entry, ok := c.entries[key]; ok {
+		return entry
+	}
+
+	entry := &regionCache{Details: map[string]cachedDetails{}}
+	c.entries[key] = entry
+
+	if c.dir == "" {
+		return entry
+	}
+
+	content, err := os.ReadFile(c.path(accountId, region))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[Warn] reading cache: %v", err)
+		}
+
+		return entry
+	}
+
+	if err := json.Unmarshal(content, entry); err != nil {
+		log.Printf("[Warn] ignoring invalid cache: %v", err)
+
+		*entry = regionCache{}
+	}
+
+	if entry.Details == nil {
+		entry.Details = map[string]cachedDetails{}
+	}
+
+	return entry
+}
+
+// withoutSecrets returns a copy of the entry with the values of the
+// environment variables masked by secrets removed.
+func (e *regionCache) withoutSecrets(secrets envMasker) *regionCache {
+	redacted := *e
+	redacted.Details = make(map[string]cachedDetails, len(e.Details))
+
+	for name, details := range e.Details {
+		details.EnvVars = slices.Clone(details.EnvVars)
+		details.Redacted = slices.Clone(details.Redacted)
+
+		for i, row := range details.EnvVars {
+			if !secrets.masks(row[0]) {
+				continue
+			}
+
+			details.EnvVars[i] = []string{row[0], ""}
+			if !slices.Contains(details.Redacted, row[0]) {
+				details.Redacted = append(details.Redacted, row[0])
+			}
+		}
+
+		redacted.Details[name] = details
+	}
+
+	return &redacted
+}
+
+// save persists the cached data of an account and region. c.mu must be held.
+func (c *lambdaCache) save(accountId string, region string) {
+	if c.dir == "" {
+		return
+	}
+
+	err := func() error {
+		content, err := json.Marshal(c.entry(accountId, region).withoutSecrets(c.secrets))
+		if err != nil {
+			return err
+		}
+
+		path := c.path(accountId, region)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+
+		// Environment variables may hold secrets, so the file is only
+		// readable by the user. CreateTemp creates files with mode 0600.
+		f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+		if err != nil {
+			return err
+		}
+
+		if _, err := f.Write(content); err != nil {
+			f.Close()
+			os.Remove(f.Name())
+
+			return err
+		}
+
+		if err := f.Close(); err != nil {
+			os.Remove(f.Name())
+
+			return err
+		}
+
+		return os.Rename(f.Name(), path)
+	}()
+	if err != nil {
+		log.Printf("[Warn] writing cache: %v", err)
+	}
+}
+
+// functions returns the cached functions of the given regions, or of the
+// session region if none are given, along with the time of the oldest update.
+func (c *lambdaCache) functions(accountId string, region string, regions []string) ([]list.Item, time.Time, bool) {
+	if c == nil {
+		return nil, time.Time{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Items are only tagged with their region in multi-region mode.
+	tags := regions
+	if len(regions) == 0 {
+		regions, tags = []string{region}, []string{""}
+	}
+
+	items := make([]list.Item, 0)
+	updated := time.Time{}
+
+	for i, region := range regions {
+		entry := c.entry(accountId, region)
+		if entry.FunctionsUpdated.IsZero() {
+			return nil, time.Time{}, false
+		}
+
+		if updated.IsZero() || entry.FunctionsUpdated.Before(updated) {
+			updated = entry.FunctionsUpdated
+		}
+
+		for _, fn := range entry.Functions {
+			items = append(items, lambdaItem{name: fn.Name, logGroup: fn.LogGroup, region: tags[i]})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].(lambdaItem).name < items[j].(lambdaItem).name
+	})
+
+	return items, updated, true
+}
+
+// storeFunctions caches the functions listed from the given regions, or from
+// the session region if none are given.
+func (c *lambdaCache) storeFunctions(accountId string, region string, regions []string, items []list.Item) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	functions := map[string][]cachedFunction{}
+	for _, item := range items {
+		fn := item.(lambdaItem)
+		key := cmp.Or(fn.region, region)
+		functions[key] = append(functions[key], cachedFunction{Name: fn.name, LogGroup: fn.logGroup})
+	}
+
+	if len(regions) == 0 {
+		regions = []string{region}
+	}
+
+	now := time.Now()
+	for _, region := range regions {
+		entry := c.entry(accountId, region)
+		entry.Functions = functions[region]
+		entry.FunctionsUpdated = now
+
+		// Details of deleted functions are dropped.
+		for name := range entry.Details {
+			if !slices.ContainsFunc(entry.Functions, func(fn cachedFunction) bool { return fn.Name == name }) {
+				delete(entry.Details, name)
+			}
+		}
+
+		c.save(accountId, region)
+	}
+}
+
+// details returns the cached details of a function.
+func (c *lambdaCache) details(accountId string, region string, name string) (lambdaInfo, time.Time, bool) {
+	if c == nil {
+		return lambdaInfo{}, time.Time{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	details, ok := c.entry(accountId, region).Details[name]
+	if !ok {
+		return lambdaInfo{}, time.Time{}, false
+	}
+
+	if details.EnvVars == nil {
+		details.EnvVars = [][]string{}
+	}
+
+	if details.Tags == nil {
+		details.Tags = [][]string{}
+	}
+
+	// The values of secrets are only known once the details are fetched.
+	envVars := slices.Clone(details.EnvVars)
+	for i, row := range envVars {
+		if slices.Contains(details.Redacted, row[0]) {
+			envVars[i] = []string{row[0], maskedValue}
+		}
+	}
+
+	return lambdaInfo{
+		arn:                 details.ARN,
+		name:                name,
+		logGroup:            details.LogGroup,
+		version:             details.Version,
+		codeSha256:          details.CodeSha256,
+		description:         details.Description,
+		lastModified:        details.LastModified,
+		runtime:             details.Runtime,
+		arch:                details.Architecture,
+		memory:              details.Memory,
+		ephemeralStorage:    details.EphemeralStorage,
+		timeout:             details.Timeout,
+		reservedConcurrency: details.ReservedConcurrency,
+		revisionId:          details.RevisionId,
+		envVars:             envVars,
+		envIncomplete:       len(details.Redacted) > 0,
+		tags:                details.Tags,
+	}, details.Updated, true
+}
+
+// storeDetails caches the details of the unqualified function.
+func (c *lambdaCache) storeDetails(accountId string, region string, info lambdaInfo) {
+	if c == nil || info.qualifier != "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entry(accountId, region).Details[info.name] = cachedDetails{
+		Updated:             time.Now(),
+		ARN:                 info.arn,
+		LogGroup:            info.logGroup,
+		Version:             info.version,
+		CodeSha256:          info.codeSha256,
+		Description:         info.description,
+		LastModified:        info.lastModified,
+		Runtime:             info.runtime,
+		Architecture:        info.arch,
+		Memory:              info.memory,
+		EphemeralStorage:    info.ephemeralStorage,
+		Timeout:             info.timeout,
+		ReservedConcurrency: info.reservedConcurrency,
+		RevisionId:          info.revisionId,
+		EnvVars:             info.envVars,
+		Tags:                info.tags,
+	}
+	c.save(accountId, region)
+}
+
+// storeReservedConcurrency updates the reserved concurrency in the cached
+// details of a function, if any.
+func (c *lambdaCache) storeReservedConcurrency(accountId string, region string, name string, reserved *int32) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entry(accountId, region)
+	details, ok := entry.Details[name]
+	if !ok {
+		return
+	}
+
+	details.ReservedConcurrency = reserved
+	entry.Details[name] = details
+	c.save(accountId, region)
+}
+
+// dropDetails removes the cached details of all functions of the given
+// regions, or of the session region if none are given.
+func (c *lambdaCache) dropDetails(accountId string, region string, regions []string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(regions) == 0 {
+		regions = []string{region}
+	}
+
+	for _, region := range regions {
+		c.entry(accountId, region).Details = map[string]cachedDetails{}
+		c.save(accountId, region)
+	}
+}
+
+// cacheAge describes how long ago a cached entry was updated.
+func cacheAge(updated time.Time) string {
+	age := time.Since(updated)
+
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// cacheStatus describes the age of cached data shown in a view, which is
+// empty if the data has been fetched since.
+func (m model) cacheStatus(updated time.Time, refreshFailed bool) string {
+	if updated.IsZero() {
+		return ""
+	}
+
+	if refreshFailed {
+		return fmt.Sprintf("cached %s ago, refresh failed", cacheAge(updated))
+	}
+
+	if m.cache.stale(updated) {
+		return fmt.Sprintf("cached %s ago, stale - refreshing", cacheAge(updated))
+	}
+
+	return fmt.Sprintf("cached %s ago", cacheAge(updated))
+}
+
+// lambdasTitle returns the title of the function list.
+func (m model) lambdasTitle() string {
+	title := fmt.Sprintf("Viewing Lambdas - %s", m.sessionTitle())
+	if status := m.cacheStatus(m.lambdasCachedAt, m.lambdasRefreshFailed); status != "" {
+		title += " (" + status + ")"
+	}
+
+	return title
+}
+
+// showCachedLambdaDetail shows the cached details of a function, which are
+// refreshed in the background if stale or lacking the values of secrets. It
+// reports whether any were cached.
+func (m *model) showCachedLambdaDetail(item lambdaItem) bool {
+	info, updated, ok := m.cache.details(m.cacheAccount(), cmp.Or(item.region, m.region), item.name)
+	if !ok {
+		return false
+	}
+
+	m.activeLambda = item.name
+	m.activeLambdaRegion = item.region
+	m.activeLambdaQualifier = ""
+	m.onRcvLambdaDetailMsg(lambdaDetailMsg{info: info})
+	m.lambdaDetailCachedAt = updated
+	m.renderLambdaDetail()
+
+	if m.cache.stale(updated) || info.envIncomplete {
+		select {
+		case m.reqCh <- lambdaDetailRefreshReq{region: item.region, name: item.name}:
+		default:
+		}
+	}
+
+	return true
+}
+
+func (m *model) onRcvLambdaListRefreshMsg(msg lambdaListRefreshMsg) {
+	// The session or mode may have been switched during the refresh.
+	if msg.account != m.cacheAccount() || msg.region != m.region || (len(msg.regions) > 0) != m.multiRegion {
+		return
+	}
+
+	m.lambdasCachedAt = time.Time{}
+	m.lambdas.SetItems(msg.items)
+	m.lambdas.Title = m.lambdasTitle()
+}
+
+func (m *model) onRcvLambdaDetailRefreshMsg(msg lambdaDetailRefreshMsg) {
+	if msg.account != m.cacheAccount() || msg.region != m.activeLambdaRegion || msg.info.name != m.activeLambda || m.activeLambdaQualifier != "" {
+		return
+	}
+
+	m.lambdaDetailInfo = msg.info
+	m.envCursor = min(m.envCursor, max(len(msg.info.envVars)-1, 0))
+	m.lambdaDetailCachedAt = time.Time{}
+	m.renderLambdaDetail()
+}
+
+func (m *model) onRcvLambdaListRefreshFailedMsg(msg lambdaListRefreshFailedMsg) {
+	if msg.account != m.cacheAccount() || msg.region != m.region || (len(msg.regions) > 0) != m.multiRegion || m.lambdasCachedAt.IsZero() {
+		return
+	}
+
+	m.lambdasRefreshFailed = true
+	m.lambdas.Title = m.lambdasTitle()
+	m.showError(fmt.Sprintf("Refreshing the cached functions failed: %v", msg.err))
+}
+
+func (m *model) onRcvLambdaDetailRefreshFailedMsg(msg lambdaDetailRefreshFailedMsg) {
+	if msg.account != m.cacheAccount() || msg.region != m.activeLambdaRegion || msg.name != m.activeLambda || m.activeLambdaQualifier != "" || m.lambdaDetailCachedAt.IsZero() {
+		return
+	}
+
+	m.detailRefreshFailed = true
+	m.renderLambdaDetail()
+	m.showError(fmt.Sprintf("Refreshing the cached details of %s failed: %v", msg.name, msg.err))
+}
+
+// handleRefreshRequest refreshes cached data in the background, concurrently
+// to the requests sent by the model.
+func handleRefreshRequest(p *tea.Program, sess session, req interface{}, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if msg := handleRequest(ctx, &sess, req); msg != nil {
+		p.Send(msg)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestLambdaCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := newLambdaCache(dir, time.Hour, envMasker{})
+
+	if _, _, ok := cache.functions("123", "eu-central-1", nil); ok {
+		t.Fatal("expected an empty cache")
+	}
+
+	cache.storeFunctions("123", "eu-central-1", nil, []list.Item{
+		lambdaItem{name: "orders-api", logGroup: "/aws/lambda/orders-api"},
+	})
+	cache.storeDetails("123", "eu-central-1", lambdaInfo{name: "orders-api", runtime: "nodejs20.x", envVars: [][]string{{"STAGE", "prod"}}})
+	// Details of versions and aliases are not cached.
+	cache.storeDetails("123", "eu-central-1", lambdaInfo{name: "payments-worker", qualifier: "prod"})
+
+	// A new cache reads the entries persisted by the first one.
+	cache = newLambdaCache(dir, time.Hour, envMasker{})
+
+	items, updated, ok := cache.functions("123", "eu-central-1", nil)
+	if !ok || len(items) != 1 || items[0].(lambdaItem).name != "orders-api" || items[0].(lambdaItem).region != "" {
+		t.Fatalf("unexpected functions: %v", items)
+	}
+
+	if cache.stale(updated) {
+		t.Fatal("expected fresh functions")
+	}
+
+	info, _, ok := cache.details("123", "eu-central-1", "orders-api")
+	if !ok || info.runtime != "nodejs20.x" || info.envVars[0][1] != "prod" {
+		t.Fatalf("unexpected details: %+v", info)
+	}
+
+	if _, _, ok := cache.details("123", "eu-central-1", "payments-worker"); ok {
+		t.Fatal("expected qualified details not to be cached")
+	}
+
+	// Other accounts and regions are cached separately.
+	if _, _, ok := cache.functions("456", "eu-central-1", nil); ok {
+		t.Fatal("expected no functions of another account")
+	}
+
+	if _, _, ok := cache.functions("123", "eu-central-1", []string{"eu-central-1", "us-east-1"}); ok {
+		t.Fatal("expected no functions unless all regions are cached")
+	}
+
+	stat, err := os.Stat(filepath.Join(dir, "123", "eu-central-1.json"))
+	if err != nil {
+		t.Fatalf("stat cache file: %v", err)
+	}
+
+	if stat.Mode().Perm() != 0o600 {
+		t.Fatalf("got mode %s, want 0600", stat.Mode().Perm())
+	}
+
+	// Removing a function drops its details.
+	cache.storeFunctions("123", "eu-central-1", nil, []list.Item{})
+	if _, _, ok := cache.details("123", "eu-central-1", "orders-api"); ok {
+		t.Fatal("expected the details of a removed function to be dropped")
+	}
+
+	if !newLambdaCache("", 0, envMasker{}).stale(time.Now().Add(-time.Second)) {
+		t.Fatal("expected entries older than the TTL to be stale")
+	}
+}
+
+func TestLambdaCacheSecrets(t *testing.T) {
+	dir := t.TempDir()
+	secrets := envMasker{mode: maskSecrets, patterns: defaultSecretPatterns}
+	cache := newLambdaCache(dir, time.Hour, secrets)
+	cache.storeDetails("123", "eu-central-1", lambdaInfo{name: "orders-api", envVars: [][]string{{"API_TOKEN", "t0ken"}, {"STAGE", "prod"}}})
+
+	// Secrets are kept in memory only.
+	info, _, _ := cache.details("123", "eu-central-1", "orders-api")
+	if info.envIncomplete || info.envVars[0][1] != "t0ken" {
+		t.Fatalf("unexpected details: %+v", info)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "123", "eu-central-1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(content), "t0ken") {
+		t.Fatalf("secret persisted: %s", content)
+	}
+
+	info, _, _ = newLambdaCache(dir, time.Hour, secrets).details("123", "eu-central-1", "orders-api")
+	if !info.envIncomplete || info.envVars[0][1] != maskedValue || info.envVars[1][1] != "prod" {
+		t.Fatalf("unexpected persisted details: %+v", info)
+	}
+}
+
+func TestCacheAccount(t *testing.T) {
+	if got := (session{accountId: "123", profile: "dev"}).cacheAccount(); got != "123" {
+		t.Fatalf("got %q, want the account id", got)
+	}
+
+	// Sessions of unknown accounts don't share their entries.
+	if got := (session{profile: "dev"}).cacheAccount(); got != "profile-dev" {
+		t.Fatalf("got %q, want the profile", got)
+	}
+}
+
+func TestLambdaCacheMultiRegion(t *testing.T) {
+	cache := newLambdaCache("", time.Hour, envMasker{})
+	cache.storeFunctions("123", "eu-central-1", []string{"eu-central-1", "us-east-1"}, []list.Item{
+		lambdaItem{name: "orders-api", region: "us-east-1"},
+		lambdaItem{name: "auth", region: "eu-central-1"},
+	})
+
+	items, _, ok := cache.functions("123", "eu-central-1", []string{"eu-central-1", "us-east-1"})
+	if !ok || len(items) != 2 || items[0].(lambdaItem).name != "auth" || items[1].(lambdaItem).region != "us-east-1" {
+		t.Fatalf("unexpected functions: %v", items)
+	}
+
+	// The session region is listed without region tags.
+	items, _, ok = cache.functions("123", "eu-central-1", nil)
+	if !ok || len(items) != 1 || items[0].(lambdaItem).region != "" {
+		t.Fatalf("unexpected functions: %v", items)
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// A cli runs the subcommands that print the data shown by the TUI instead of
+// starting it.
+type cli struct {
+	sess session
+	// listRegions are the regions to list functions from, which is empty
+	// unless multi-region mode is enabled.
+	listRegions []string
+	masker      envMasker
+	out         io.Writer
+}
+
+// commandUsage describes the subcommands for the usage message.
+const commandUsage = `Commands:
+  list                     list functions
+  describe <function>      show the configuration of a function
+  streams <function>       list the log streams of a function
+  logs <function>          print the events of a log stream
+
+Run "lambdatui <command> -h" for the flags of a command.
+`
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// run runs the subcommand named by the first argument.
+func (c cli) run(ctx context.Context, args []string) error {
+	var err error
+
+	switch args[0] {
+	case "list":
+		err = c.list(ctx, args[1:])
+	case "describe":
+		err = c.describe(ctx, args[1:])
+	case "streams":
+		err = c.streams(ctx, args[1:])
+	case "logs":
+		err = c.logs(ctx, args[1:])
+	default:
+		err = fmt.Errorf("unknown command %q\n\n%s", args[0], commandUsage)
+	}
+
+	// The usage has already been printed if requested by -h.
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	return err
+}
+
+// newFlagSet returns the flag set of a subcommand along with its -output and
+// -region flags.
+func newFlagSet(name string, usage string) (*flag.FlagSet, *string, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: lambdatui %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+
+	output := fs.String("output", outputTable, "output format: table or json")
+	region := fs.String("region", "", "region of the function, defaults to the session region")
+
+	return fs, output, region
+}
+
+// parseArgs parses flags that may follow positional arguments and checks that
+// exactly n positional arguments are given.
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	positional := make([]string, 0, n)
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		if fs.NArg() == 0 {
+			break
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != n {
+		fs.Usage()
+
+		return nil, fmt.Errorf("%s expects %d arguments, got %d", fs.Name(), n, len(positional))
+	}
+
+	if output := fs.Lookup("output").Value.String(); output != outputTable && output != outputJSON {
+		return nil, fmt.Errorf("invalid output format %q, expected table or json", output)
+	}
+
+	return positional, nil
+}
+
+// print writes v as JSON or calls table to write it as a table.
+func (c cli) print(output string, v any, table func(w io.Writer)) error {
+	if output == outputJSON {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	table(w)
+
+	return w.Flush()
+}
+
+type functionOutput struct {
+	Name     string `json:"name"`
+	Region   string `json:"region,omitempty"`
+	LogGroup string `json:"logGroup"`
+}
+
+func (c cli) list(ctx context.Context, args []string) error {
+	fs, output, region := newFlagSet("list", "[flags]")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	// -region lists the functions of a single region, also in multi-region
+	// mode.
+	regions := c.listRegions
+	if *region != "" {
+		regions = []string{*region}
+	}
+
+	items, err := listLambdaFunctions(ctx, c.sess, regions)
+	if err != nil {
+		return err
+	}
+
+	functions := make([]functionOutput, 0, len(items))
+	for _, item := range items {
+		fn := item.(lambdaItem)
+		functions = append(functions, functionOutput{Name: fn.name, Region: fn.region, LogGroup: fn.logGroup})
+	}
+
+	return c.print(*output, functions, func(w io.Writer) {
+		if len(c.listRegions) > 0 {
+			fmt.Fprintln(w, "NAME\tREGION\tLOG GROUP")
+		} else {
+			fmt.Fprintln(w, "NAME\tLOG GROUP")
+		}
+
+		for _, fn := range functions {
+			if len(c.listRegions) > 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", fn.Name, fn.Region, fn.LogGroup)
+			} else {
+				fmt.Fprintf(w, "%s\t%s\n", fn.Name, fn.LogGroup)
+			}
+		}
+	})
+}
+
+type functionInfoOutput struct {
+	ARN                 string            `json:"arn"`
+	Name                string            `json:"name"`
+	Version             string            `json:"version"`
+	CodeSha256          string            `json:"codeSha256"`
+	Description         string            `json:"description"`
+	LastModified        string            `json:"lastModified"`
+	Runtime             string            `json:"runtime"`
+	Architecture        string            `json:"architecture"`
+	MemorySize          uint32            `json:"memorySize"`
+	EphemeralStorage    uint32            `json:"ephemeralStorage"`
+	Timeout             uint32            `json:"timeout"`
+	LogGroup            string            `json:"logGroup"`
+	ReservedConcurrency *int32            `json:"reservedConcurrency,omitempty"`
+	Environment         map[string]string `json:"environment"`
+	Tags                map[string]string `json:"tags"`
+}
+
+func (c cli) describe(ctx context.Context, args []string) error {
+	fs, output, region := newFlagSet("describe", "[flags] <function>")
+	qualifier := fs.String("qualifier", "", "version or alias of the function")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	info, err := getLambdaInfo(ctx, c.sess.lambdaClient(*region), positional[0], *qualifier)
+	if err != nil {
+		return err
+	}
+
+	// Environment variable values are masked like in the detail view.
+	envVars := make([][]string, 0, len(info.envVars))
+	env := make(map[string]string, len(info.envVars))
+	for _, row := range info.envVars {
+		value := row[1]
+		if c.masker.masks(row[0]) {
+			value = maskedValue
+		}
+
+		envVars = append(envVars, []string{row[0], value})
+		env[row[0]] = value
+	}
+
+	tags := make(map[string]string, len(info.tags))
+	for _, row := range info.tags {
+		tags[row[0]] = row[1]
+	}
+
+	out := functionInfoOutput{
+		ARN:                 info.arn,
+		Name:                info.name,
+		Version:             info.version,
+		CodeSha256:          info.codeSha256,
+		Description:         info.description,
+		LastModified:        info.lastModified,
+		Runtime:             info.runtime,
+		Architecture:        info.arch,
+		MemorySize:          info.memory,
+		EphemeralStorage:    info.ephemeralStorage,
+		Timeout:             info.timeout,
+		LogGroup:            info.logGroup,
+		ReservedConcurrency: info.reservedConcurrency,
+		Environment:         env,
+		Tags:                tags,
+	}
+
+	return c.print(*output, out, func(w io.Writer) {
+		rows := [][]string{
+			{"ARN", info.arn},
+			{"Name", info.name},
+			{"Version", info.version},
+			{"Code SHA256", info.codeSha256},
+			{"Last Modified", info.lastModified},
+			{"Runtime", info.runtime},
+			{"Architecture", info.arch},
+			{"Memory Size", fmt.Sprintf("%d MB", info.memory)},
+			{"Ephemeral Storage", fmt.Sprintf("%d MB", info.ephemeralStorage)},
+			{"Timeout", fmt.Sprintf("%d seconds", info.timeout)},
+			{"Log Group", info.logGroup},
+			{"Reserved Concurrency", formatReservedConcurrency(info.reservedConcurrency)},
+		}
+
+		for _, row := range rows {
+			fmt.Fprintf(w, "%s\t%s\n", row[0], row[1])
+		}
+
+		fmt.Fprintln(w, "\nENVIRONMENT")
+		for _, row := range envVars {
+			fmt.Fprintf(w, "%s\t%s\n", row[0], row[1])
+		}
+
+		fmt.Fprintln(w, "\nTAGS")
+		for _, row := range info.tags {
+			fmt.Fprintf(w, "%s\t%s\n", row[0], row[1])
+		}
+	})
+}
+
+type logStreamOutput struct {
+	Name      string `json:"name"`
+	LastEvent string `json:"lastEvent,omitempty"`
+	Expired   bool   `json:"expired"`
+}
+
+// functionLogGroup returns the log group of a function.
+func (c cli) functionLogGroup(ctx context.Context, region string, name string) (string, error) {
+	info, err := getLambdaInfo(ctx, c.sess.lambdaClient(region), name, "")
+	if err != nil {
+		return "", err
+	}
+
+	if info.logGroup == "" {
+		return "", fmt.Errorf("function %s has no log group", name)
+	}
+
+	return info.logGroup, nil
+}
+
+func (c cli) streams(ctx context.Context, args []string) error {
+	fs, output, region := newFlagSet("streams", "[flags] <function>")
+	limit := fs.Int("limit", 50, "maximum number of streams, 0 lists all streams")
+	version := fs.String("version", "", "only list the streams of this function version")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	logGroup, err := c.functionLogGroup(ctx, *region, positional[0])
+	if err != nil {
+		return err
+	}
+
+	var versions []string
+	if *version != "" {
+		versions = []string{*version}
+	}
+
+	streams := make([]logStreamOutput, 0)
+	nextToken := ""
+	for {
+		page, token, err := getLogStreams(ctx, c.sess.cloudwatchClient(*region), logGroup, nextToken, versions)
+		if err != nil {
+			return err
+		}
+
+		for _, stream := range page {
+			lastEvent := stream.lastEventTimestamp
+			if lastEvent == "-" {
+				lastEvent = ""
+			}
+
+			streams = append(streams, logStreamOutput{Name: stream.name, LastEvent: lastEvent, Expired: stream.expired})
+		}
+
+		if token == "" || (*limit > 0 && len(streams) >= *limit) {
+			break
+		}
+
+		nextToken = token
+	}
+
+	if *limit > 0 && len(streams) > *limit {
+		streams = streams[:*limit]
+	}
+
+	return c.print(*output, streams, func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tLAST EVENT")
+		for _, stream := range streams {
+			lastEvent := stream.LastEvent
+			if stream.Expired {
+				lastEvent += " (expired)"
+			}
+
+			fmt.Fprintf(w, "%s\t%s\n", stream.Name, lastEvent)
+		}
+	})
+}
+
+type logEventOutput struct {
+	Timestamp string `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+func (c cli) logs(ctx context.Context, args []string) error {
+	fs, output, region := newFlagSet("logs", "[flags] <function>")
+	stream := fs.String("stream", "", "log stream to print, defaults to the most recently active one")
+	since := fs.Duration("since", 0, "print the events of this period, e.g. 1h, instead of the most recent page")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	logGroup, err := c.functionLogGroup(ctx, *region, positional[0])
+	if err != nil {
+		return err
+	}
+
+	cw := c.sess.cloudwatchClient(*region)
+	if *stream == "" {
+		streams, _, err := getLogStreams(ctx, cw, logGroup, "", nil)
+		if err != nil {
+			return err
+		}
+
+		if len(streams) == 0 {
+			return errors.New("no log streams found")
+		}
+
+		*stream = streams[0].name
+	}
+
+	var start time.Time
+	if *since > 0 {
+		start = time.Now().Add(-*since)
+	}
+
+	page, err := getLogEvents(ctx, cw, logGroup, *stream, "", start)
+	if err != nil {
+		return err
+	}
+
+	rows := page.events
+	// Without -since only the most recent page is printed, like in the TUI.
+	for !start.IsZero() && page.backwardToken != "" {
+		page, err = getLogEvents(ctx, cw, logGroup, *stream, page.backwardToken, start)
+		if err != nil {
+			return err
+		}
+
+		rows = append(page.events, rows...)
+	}
+
+	events := make([]logEventOutput, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, logEventOutput{Timestamp: row[0], Message: row[1]})
+	}
+
+	if *output == outputJSON {
+		return c.print(*output, events, nil)
+	}
+
+	// Messages may span multiple lines and are not aligned in a table.
+	for _, event := range events {
+		if _, err := fmt.Fprintf(c.out, "%s  %s\n", event.Timestamp, event.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
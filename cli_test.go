@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func runCLI(t *testing.T, backend *fakeBackend, args ...string) string {
+	t.Helper()
+
+	var b strings.Builder
+	c := cli{sess: backend.session(), masker: envMasker{mode: maskSecrets, patterns: defaultSecretPatterns}, out: &b}
+	if err := c.run(context.Background(), args); err != nil {
+		t.Fatalf("%s: %v", strings.Join(args, " "), err)
+	}
+
+	return b.String()
+}
+
+func TestCLIList(t *testing.T) {
+	out := runCLI(t, newScriptedBackend(), "list")
+	for _, s := range []string{"NAME", "orders-api", "/aws/lambda/payments-worker"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("output does not contain %q:\n%s", s, out)
+		}
+	}
+
+	var functions []functionOutput
+	if err := json.Unmarshal([]byte(runCLI(t, newScriptedBackend(), "list", "--output", "json")), &functions); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+
+	if len(functions) != 2 || functions[0].Name != "orders-api" || functions[0].LogGroup != fakeLogGroup("orders-api") {
+		t.Fatalf("unexpected functions: %+v", functions)
+	}
+
+	// The functions of other regions are served by empty backends.
+	if out := runCLI(t, newScriptedBackend(), "list", "-region", "us-east-1"); strings.Contains(out, "orders-api") {
+		t.Fatalf("-region was ignored:\n%s", out)
+	}
+}
+
+func TestCLIDescribe(t *testing.T) {
+	backend := newScriptedBackend()
+	backend.addFunction(fakeFunction{
+		name:    "auth",
+		runtime: lambdatypes.RuntimeNodejs20x,
+		env:     map[string]string{"API_TOKEN": "hunter2", "STAGE": "prod"},
+	})
+
+	// Flags may follow the function name.
+	var info functionInfoOutput
+	if err := json.Unmarshal([]byte(runCLI(t, backend, "describe", "auth", "-output", "json")), &info); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+
+	if info.Name != "auth" || info.LogGroup != fakeLogGroup("auth") {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	if info.Environment["API_TOKEN"] != maskedValue || info.Environment["STAGE"] != "prod" {
+		t.Fatalf("unexpected environment: %v", info.Environment)
+	}
+
+	out := runCLI(t, backend, "describe", "orders-api")
+	for _, s := range []string{"Memory Size", "512 MB", "TABLE_NAME", "orders", "team"} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("output does not contain %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestCLIStreams(t *testing.T) {
+	out := runCLI(t, newPagedBackend(), "streams", "orders-api", "-limit", "3")
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 4 {
+		t.Fatalf("got %d lines, want header and 3 streams:\n%s", len(lines), out)
+	}
+
+	var streams []logStreamOutput
+	if err := json.Unmarshal([]byte(runCLI(t, newPagedBackend(), "streams", "orders-api", "-limit", "0", "-output", "json")), &streams); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+
+	if len(streams) != 5 || streams[0].Name != "stream-4" {
+		t.Fatalf("unexpected streams: %+v", streams)
+	}
+}
+
+func TestCLILogs(t *testing.T) {
+	out := runCLI(t, newScriptedBackend(), "logs", "orders-api")
+	if !strings.Contains(out, "order created") || strings.Contains(out, "older stream") {
+		t.Fatalf("expected the events of the most recent stream:\n%s", out)
+	}
+
+	out = runCLI(t, newScriptedBackend(), "logs", "orders-api", "-stream", "2024/08/31/[$LATEST]bbbb")
+	if !strings.Contains(out, "older stream") {
+		t.Fatalf("expected the events of the given stream:\n%s", out)
+	}
+
+	backend := newFakeBackend("eu-central-1")
+	backend.pageSize = 1
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+
+	stream := fakeStream{name: "stream"}
+	for i, age := range []time.Duration{3 * time.Hour, 90 * time.Minute, 30 * time.Minute, 10 * time.Minute} {
+		stream.events = append(stream.events, fakeEvent{
+			timestamp: time.Now().Add(-age).UnixMilli(),
+			message:   fmt.Sprintf("event %d", i),
+		})
+	}
+	backend.addStream(fakeLogGroup("orders-api"), stream)
+
+	// All pages of the period are fetched.
+	var events []logEventOutput
+	if err := json.Unmarshal([]byte(runCLI(t, backend, "logs", "orders-api", "-since", "1h", "-output", "json")), &events); err != nil {
+		t.Fatalf("decoding output: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Message != "event 2" || events[1].Message != "event 3" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	stack := middleware.NewStack("test", func() interface{} { return nil })
+	if err := withCallTimeout(time.Minute)(stack); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	handler := middleware.HandlerFunc(func(ctx context.Context, _ interface{}) (interface{}, middleware.Metadata, error) {
+		calls++
+
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Fatalf("call has deadline %v, want one within a minute", deadline)
+		}
+
+		return nil, middleware.Metadata{}, nil
+	})
+
+	for range 2 {
+		if _, _, err := stack.HandleMiddleware(context.Background(), nil, handler); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+}
+
+func TestCLIInvalidArgs(t *testing.T) {
+	c := cli{sess: newScriptedBackend().session(), out: &strings.Builder{}}
+
+	for _, args := range [][]string{{"unknown"}, {"describe"}, {"list", "-output", "yaml"}} {
+		if err := c.run(context.Background(), args); err == nil {
+			t.Errorf("%s: expected an error", strings.Join(args, " "))
+		}
+	}
+}
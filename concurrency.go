@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+type concurrencyReq struct {
+	region string
+	name   string
+}
+
+type concurrencyMsg struct {
+	info concurrencyInfo
+}
+
+// A concurrency update request applies a change to the concurrency settings
+// of a function and responds with the updated settings.
+type concurrencyUpdateReq struct {
+	region string
+	name   string
+	change concurrencyChange
+}
+
+// A concurrencyChange sets the reserved concurrency of a function or, if a
+// qualifier is given, the provisioned concurrency of a version or alias. A
+// nil value removes the setting.
+type concurrencyChange struct {
+	qualifier string
+	value     *int32
+}
+
+// The prompts of the concurrency view.
+const (
+	concurrencyPromptReserved    = "reserved"
+	concurrencyPromptProvisioned = "provisioned"
+	concurrencyPromptAdd         = "add"
+)
+
+// describe describes the change to the concurrency settings of a function.
+func (c concurrencyChange) describe(name string) string {
+	switch {
+	case c.qualifier == "" && c.value == nil:
+		return fmt.Sprintf("Remove the reserved concurrency of \"%s\"", name)
+	case c.qualifier == "" && *c.value == 0:
+		return fmt.Sprintf("Reserve 0 concurrent executions for \"%s\", which throttles all invocations", name)
+	case c.qualifier == "":
+		return fmt.Sprintf("Reserve %d concurrent executions for \"%s\"", *c.value, name)
+	case c.value == nil:
+		return fmt.Sprintf("Remove the provisioned concurrency of \"%s:%s\"", name, c.qualifier)
+	default:
+		return fmt.Sprintf("Provision %d concurrent executions for \"%s:%s\"", *c.value, name, c.qualifier)
+	}
+}
+
+// parseConcurrency parses a number of concurrent executions of at least min.
+func parseConcurrency(s string, min int32) (int32, error) {
+	value, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+	if err != nil || int32(value) < min {
+		return 0, fmt.Errorf("expected a number of concurrent executions of at least %d, got %q", min, strings.TrimSpace(s))
+	}
+
+	return int32(value), nil
+}
+
+// formatReservedConcurrency describes the reserved concurrency of a function.
+func formatReservedConcurrency(reserved *int32) string {
+	if reserved == nil {
+		return "unreserved"
+	}
+
+	return strconv.Itoa(int(*reserved))
+}
+
+// requestConcurrency fetches the concurrency settings of the function shown in
+// the detail view.
+func (m *model) requestConcurrency() tea.Cmd {
+	select {
+	case m.reqCh <- m.tag(concurrencyReq{region: m.activeLambdaRegion, name: m.activeLambda}):
+		return m.startLoading()
+	default:
+		return nil
+	}
+}
+
+func (m model) viewConcurrencyUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.concurrencyPrompt != "" {
+		return m.viewConcurrencyPromptUpdate(msg)
+	}
+
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.activeView = viewLambdaDetail
+		case key.Matches(msg, m.keys.up):
+			m.concurrencyCursor = max(m.concurrencyCursor-1, 0)
+		case key.Matches(msg, m.keys.down):
+			m.concurrencyCursor = min(m.concurrencyCursor+1, len(m.concurrency.provisioned))
+		case key.Matches(msg, m.keys.refresh):
+			cmd = m.requestConcurrency()
+		case key.Matches(msg, m.keys.edit):
+			if m.concurrencyCursor == 0 {
+				cmd = m.openConcurrencyPrompt(concurrencyPromptReserved, "reserve: ", formatReservedConcurrency(m.concurrency.reserved))
+			} else {
+				config := m.concurrency.provisioned[m.concurrencyCursor-1]
+				cmd = m.openConcurrencyPrompt(concurrencyPromptProvisioned, "provision for "+config.qualifier+": ", strconv.Itoa(int(config.requested)))
+			}
+		case key.Matches(msg, m.keys.add):
+			cmd = m.openConcurrencyPrompt(concurrencyPromptAdd, "provision (alias or version=executions): ", "")
+		case key.Matches(msg, m.keys.remove):
+			if m.concurrencyCursor == 0 {
+				if m.concurrency.reserved == nil {
+					m.showError("No concurrency is reserved")
+					break
+				}
+
+				m.confirmConcurrencyChange(concurrencyChange{})
+			} else {
+				m.confirmConcurrencyChange(concurrencyChange{qualifier: m.concurrency.provisioned[m.concurrencyCursor-1].qualifier})
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) openConcurrencyPrompt(prompt string, label string, value string) tea.Cmd {
+	if value == "unreserved" {
+		value = ""
+	}
+
+	m.concurrencyPrompt = prompt
+	m.concurrencyInput.Prompt = label
+	m.concurrencyInput.SetValue(value)
+	m.concurrencyInput.CursorEnd()
+
+	return m.concurrencyInput.Focus()
+}
+
+func (m model) viewConcurrencyPromptUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.concurrencyPrompt = ""
+			m.concurrencyInput.Blur()
+
+			return m, nil
+		case key.Matches(msg, m.keys.choose):
+			change, err := m.parseConcurrencyPrompt()
+			if err != nil {
+				m.showError(err.Error())
+
+				return m, nil
+			}
+
+			m.concurrencyPrompt = ""
+			m.concurrencyInput.Blur()
+			m.confirmConcurrencyChange(change)
+
+			return m, nil
+		}
+	}
+
+	m.concurrencyInput, cmd = m.concurrencyInput.Update(msg)
+
+	return m, cmd
+}
+
+// parseConcurrencyPrompt returns the change entered into the open prompt.
+func (m model) parseConcurrencyPrompt() (concurrencyChange, error) {
+	input := m.concurrencyInput.Value()
+
+	switch m.concurrencyPrompt {
+	case concurrencyPromptReserved:
+		value, err := parseConcurrency(input, 0)
+
+		return concurrencyChange{value: &value}, err
+	case concurrencyPromptProvisioned:
+		value, err := parseConcurrency(input, 1)
+
+		return concurrencyChange{qualifier: m.concurrency.provisioned[m.concurrencyCursor-1].qualifier, value: &value}, err
+	default:
+		qualifier, count, ok := strings.Cut(input, "=")
+		qualifier = strings.TrimSpace(qualifier)
+		if !ok || qualifier == "" {
+			return concurrencyChange{}, fmt.Errorf("expected alias or version=executions, got %q", input)
+		}
+
+		value, err := parseConcurrency(count, 1)
+
+		return concurrencyChange{qualifier: qualifier, value: &value}, err
+	}
+}
+
+func (m *model) confirmConcurrencyChange(change concurrencyChange) {
+	m.concurrencyChange = change
+	m.activeView = viewConcurrencyConfirm
+}
+
+func (m model) viewConcurrencyConfirmUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.activeView = viewConcurrency
+		case key.Matches(msg, m.keys.choose, m.keys.apply):
+			select {
+			case m.reqCh <- m.tag(concurrencyUpdateReq{region: m.activeLambdaRegion, name: m.activeLambda, change: m.concurrencyChange}):
+				cmd = m.startLoading()
+			default:
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvConcurrencyMsg(msg concurrencyMsg) {
+	m.concurrency = msg.info
+	m.concurrencyCursor = min(m.concurrencyCursor, len(msg.info.provisioned))
+
+	// The detail view shows the reserved concurrency as well.
+	m.lambdaDetailInfo.reservedConcurrency = msg.info.reserved
+	m.renderLambdaDetail()
+
+	m.activeView = viewConcurrency
+	m.loading = false
+}
+
+func (m model) concurrencyView() string {
+	cursorStyleFunc := func(row, col int) lipgloss.Style {
+		// Data rows are numbered from one.
+		if row == m.concurrencyCursor+1 && col == 0 {
+			return lambdaDetailSelectedFieldNameStyle
+		}
+
+		return lambdaDetailTableStyleFunc(row, col)
+	}
+
+	reserved := table.
+		New().
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(cursorStyleFunc).
+		Rows([]string{"Reserved", formatReservedConcurrency(m.concurrency.reserved)})
+
+	rows := []string{
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Concurrency - Function \"%s\" - %s", m.activeLambda, m.sessionTitle())),
+		"",
+		lambdaDetailTitleStyle.Render("Reserved Concurrency"),
+		reserved.Render(),
+		lambdaDetailTitleStyle.Render("Provisioned Concurrency"),
+	}
+
+	if len(m.concurrency.provisioned) == 0 {
+		rows = append(rows, helpStyle.Render("none"))
+	} else {
+		configRows := make([][]string, 0, len(m.concurrency.provisioned))
+		for _, config := range m.concurrency.provisioned {
+			status := config.status
+			if config.reason != "" {
+				status += ": " + config.reason
+			}
+
+			configRows = append(configRows, []string{
+				config.qualifier,
+				strconv.Itoa(int(config.requested)),
+				strconv.Itoa(int(config.allocated)),
+				strconv.Itoa(int(config.available)),
+				status,
+			})
+		}
+
+		provisioned := table.
+			New().
+			Border(lipgloss.HiddenBorder()).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == 0 {
+					return insightsHeaderStyle
+				}
+
+				// The reserved concurrency precedes the configs.
+				if row == m.concurrencyCursor && col == 0 {
+					return lambdaDetailSelectedFieldNameStyle
+				}
+
+				return lambdaDetailTableStyleFunc(row, col)
+			}).
+			Headers("Qualifier", "Requested", "Allocated", "Available", "Status").
+			Rows(configRows...)
+
+		rows = append(rows, provisioned.Render())
+	}
+
+	help := helpLine(
+		keyHelp(m.keys.up, ""),
+		keyHelp(m.keys.down, ""),
+		keyHelp(m.keys.edit, ""),
+		keyHelp(m.keys.remove, ""),
+		keyHelp(m.keys.add, ""),
+		keyHelp(m.keys.refresh, ""),
+		keyHelp(m.keys.back, ""),
+	)
+	if m.concurrencyPrompt != "" {
+		help = m.concurrencyInput.View() + helpLine(keyHelp(m.keys.choose, "review"), keyHelp(m.keys.back, "cancel"))
+	}
+
+	rows = append(rows, "", help)
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m model) concurrencyConfirmView() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(m.concurrencyChange.describe(m.activeLambda)+"?"),
+		"",
+		helpLine(keyHelp(m.keys.choose, "apply"), keyHelp(m.keys.apply, ""), keyHelp(m.keys.back, "")),
+	)
+}
+
+func newConcurrencyInput() textinput.Model {
+	t := textinput.New()
+	t.Placeholder = "executions"
+
+	return t
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// An env update request replaces the environment variables of a function and
+// responds with its updated details.
+type envUpdateReq struct {
+	region string
+	name   string
+	// revisionId is the revision of the configuration the changes were made
+	// to.
+	revisionId string
+	vars       map[string]string
+}
+
+// An envChange is the change of a single environment variable. old is empty
+// for added variables, new for deleted ones.
+type envChange struct {
+	key     string
+	old     string
+	new     string
+	added   bool
+	deleted bool
+}
+
+var envKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+var (
+	envAddedStyle   = lipgloss.NewStyle()
+	envDeletedStyle = lipgloss.NewStyle()
+	envChangedStyle = lipgloss.NewStyle()
+)
+
+// parseEnvVars parses environment variables from lines of the form
+// KEY=value. Empty lines and lines starting with # are ignored. Whitespace
+// around keys and values is ignored as well. Values starting with a double
+// quote are Go string literals, which allows values spanning multiple lines
+// or with surrounding whitespace like KEY="line 1\nline 2 ".
+func parseEnvVars(s string) (map[string]string, error) {
+	vars := map[string]string{}
+
+	for i, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value", i+1)
+		}
+
+		if !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", i+1, key)
+		}
+
+		if _, ok := vars[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate variable %s", i+1, key)
+		}
+
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value of %s", i+1, key)
+			}
+
+			value = unquoted
+		}
+
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+// formatEnvVars renders rows of environment variables in the format read by
+// parseEnvVars. Values that span multiple lines, start with a double quote or
+// have surrounding whitespace are quoted.
+func formatEnvVars(rows [][]string) string {
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		value := row[1]
+		if strings.ContainsAny(value, "\r\n") || strings.HasPrefix(value, `"`) || strings.TrimSpace(value) != value {
+			value = strconv.Quote(value)
+		}
+
+		lines = append(lines, row[0]+"="+value)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// diffEnvVars returns the changes from old to new ordered by key.
+func diffEnvVars(old map[string]string, new map[string]string) []envChange {
+	changes := make([]envChange, 0)
+
+	for key, value := range old {
+		newValue, ok := new[key]
+		switch {
+		case !ok:
+			changes = append(changes, envChange{key: key, old: value, deleted: true})
+		case newValue != value:
+			changes = append(changes, envChange{key: key, old: value, new: newValue})
+		}
+	}
+
+	for key, value := range new {
+		if _, ok := old[key]; !ok {
+			changes = append(changes, envChange{key: key, new: value, added: true})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b envChange) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return changes
+}
+
+// editEnvVars opens the editor for the environment variables of the function
+// shown in the detail view.
+func (m *model) editEnvVars() tea.Cmd {
+	if m.activeLambdaQualifier != "" {
+		m.showError("Only the environment variables of $LATEST can be edited")
+
+		return nil
+	}
+
+	if m.lambdaDetailInfo.envIncomplete {
+		m.showError("The values of secrets are still being loaded, try again once the details are refreshed")
+
+		return nil
+	}
+
+	m.envEditorRevealed = false
+	m.envEditor.SetValue(m.maskEnvLines(formatEnvVars(m.lambdaDetailInfo.envVars), false))
+	m.activeView = viewEnvEdit
+
+	return m.envEditor.Focus()
+}
+
+func (m model) viewEnvEditUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.envEditor.Blur()
+			m.activeView = viewLambdaDetail
+
+			return m, nil
+		case key.Matches(msg, m.keys.run):
+			vars, err := parseEnvVars(m.envEditor.Value())
+			if err != nil {
+				m.showError(err.Error())
+
+				return m, nil
+			}
+
+			old := make(map[string]string, len(m.lambdaDetailInfo.envVars))
+			for _, row := range m.lambdaDetailInfo.envVars {
+				old[row[0]] = row[1]
+			}
+
+			// Masked values that have been left unchanged keep their value.
+			for key, value := range vars {
+				if oldValue, ok := old[key]; ok && value == maskedValue {
+					vars[key] = oldValue
+				}
+			}
+
+			m.envChanges = diffEnvVars(old, vars)
+			if len(m.envChanges) == 0 {
+				m.showError("No changes to apply")
+
+				return m, nil
+			}
+
+			m.envVars = vars
+			m.envDiffRevealed = false
+			m.envEditor.Blur()
+			m.activeView = viewEnvDiff
+
+			return m, nil
+		case key.Matches(msg, m.keys.revealSecrets):
+			m.envEditorRevealed = !m.envEditorRevealed
+			m.envEditor.SetValue(m.maskEnvLines(m.envEditor.Value(), m.envEditorRevealed))
+
+			return m, nil
+		}
+	}
+
+	m.envEditor, cmd = m.envEditor.Update(msg)
+
+	return m, cmd
+}
+
+// maskEnvLines replaces the values of masked variables on the lines of the
+// editor with maskedValue, or maskedValue with the values if reveal is set.
+// Lines that have been edited are left unchanged.
+func (m model) maskEnvLines(s string, reveal bool) string {
+	lines := strings.Split(s, "\n")
+
+	for _, row := range m.lambdaDetailInfo.envVars {
+		if m.envValue(row[0], row[1]) != maskedValue {
+			continue
+		}
+
+		masked, revealed := row[0]+"="+maskedValue, formatEnvVars([][]string{row})
+		for i, line := range lines {
+			switch {
+			case reveal && line == masked:
+				lines[i] = revealed
+			case !reveal && line == revealed:
+				lines[i] = masked
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m model) viewEnvDiffUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.activeView = viewEnvEdit
+			cmd = m.envEditor.Focus()
+		case key.Matches(msg, m.keys.revealAll):
+			m.envDiffRevealed = !m.envDiffRevealed
+		case key.Matches(msg, m.keys.choose, m.keys.apply):
+			select {
+			case m.reqCh <- m.tag(envUpdateReq{
+				region:     m.activeLambdaRegion,
+				name:       m.activeLambda,
+				revisionId: m.lambdaDetailInfo.revisionId,
+				vars:       m.envVars,
+			}):
+				cmd = m.startLoading()
+			default:
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m model) envEditView() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Environment Variables \"%s\" - %s", m.activeLambda, m.sessionTitle())),
+		"",
+		m.envEditor.View(),
+		"",
+		helpLine(
+			`one KEY=value per line, KEY="…\n…" for multiple lines`,
+			keyHelp(m.keys.run, "review changes"),
+			keyHelp(m.keys.revealSecrets, ""),
+			keyHelp(m.keys.back, ""),
+		),
+	)
+}
+
+// envDiffValue returns the value of an environment variable as it is shown in
+// the diff, where all values may be revealed without revealing them in the
+// detail view.
+func (m model) envDiffValue(key string, value string) string {
+	if m.envDiffRevealed {
+		return value
+	}
+
+	return m.envValue(key, value)
+}
+
+func (m model) envDiffView() string {
+	lines := make([]string, 0, len(m.envChanges))
+	for _, change := range m.envChanges {
+		switch {
+		case change.added:
+			lines = append(lines, envAddedStyle.Render(fmt.Sprintf("+ %s=%s", change.key, m.envDiffValue(change.key, change.new))))
+		case change.deleted:
+			lines = append(lines, envDeletedStyle.Render(fmt.Sprintf("- %s=%s", change.key, m.envDiffValue(change.key, change.old))))
+		default:
+			lines = append(lines, envChangedStyle.Render(fmt.Sprintf(
+				"~ %s=%s → %s",
+				change.key,
+				m.envDiffValue(change.key, change.old),
+				m.envDiffValue(change.key, change.new),
+			)))
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Apply %d changes to \"%s\"?", len(m.envChanges), m.activeLambda)),
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		helpLine(keyHelp(m.keys.choose, "apply"), keyHelp(m.keys.apply, ""), keyHelp(m.keys.revealAll, ""), keyHelp(m.keys.back, "edit")),
+	)
+}
+
+// newEnvEditor returns the text area used to edit environment variables.
+func newEnvEditor() textarea.Model {
+	t := textarea.New()
+	t.CharLimit = 0
+	t.MaxHeight = 0
+	t.ShowLineNumbers = true
+
+	return t
+}
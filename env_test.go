@@ -0,0 +1,102 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseEnvVars(t *testing.T) {
+	vars, err := parseEnvVars("# comment\nA=1\n\nB = x=y \nC=\nD=\" padded \"")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Whitespace around = is ignored unless quoted.
+	if want := map[string]string{"A": "1", "B": "x=y", "C": "", "D": " padded "}; !maps.Equal(vars, want) {
+		t.Fatalf("got %v, want %v", vars, want)
+	}
+
+	for _, invalid := range []string{"A", "1A=x", "A=1\nA=2", "A-B=1", `A="unterminated`} {
+		if _, err := parseEnvVars(invalid); err == nil {
+			t.Errorf("parseEnvVars(%q) did not fail", invalid)
+		}
+	}
+}
+
+func TestFormatEnvVars(t *testing.T) {
+	rows := [][]string{
+		{"CERT", "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"},
+		{"JSON", `"quoted"`},
+		{"PATH", `C:\bin`},
+		{"PADDED", " x "},
+	}
+
+	formatted := formatEnvVars(rows)
+	if want := `CERT="-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"` + "\n" +
+		`JSON="\"quoted\""` + "\n" +
+		`PATH=C:\bin` + "\n" +
+		`PADDED=" x "`; formatted != want {
+		t.Fatalf("got %q, want %q", formatted, want)
+	}
+
+	vars, err := parseEnvVars(formatted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range rows {
+		if vars[row[0]] != row[1] {
+			t.Errorf("%s round-tripped to %q, want %q", row[0], vars[row[0]], row[1])
+		}
+	}
+}
+
+func TestDiffEnvVars(t *testing.T) {
+	changes := diffEnvVars(
+		map[string]string{"KEEP": "1", "CHANGE": "old", "DELETE": "x"},
+		map[string]string{"KEEP": "1", "CHANGE": "new", "ADD": "y"},
+	)
+
+	want := []envChange{
+		{key: "ADD", new: "y", added: true},
+		{key: "CHANGE", old: "old", new: "new"},
+		{key: "DELETE", old: "x", deleted: true},
+	}
+
+	if !slices.Equal(changes, want) {
+		t.Fatalf("got %+v, want %+v", changes, want)
+	}
+}
+
+func TestEnvMasker(t *testing.T) {
+	secrets, err := newEnvMasker("secrets", []string{"*SECRET*", "db_*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]bool{
+		"CLIENT_SECRET": true,
+		"secret_key":    true,
+		"DB_HOST":       true,
+		"TABLE_NAME":    false,
+	} {
+		if got := secrets.masks(key); got != want {
+			t.Errorf("masks(%q) = %t, want %t", key, got, want)
+		}
+	}
+
+	all, _ := newEnvMasker("all", nil)
+	none, _ := newEnvMasker("none", []string{"*"})
+	if !all.masks("TABLE_NAME") || none.masks("CLIENT_SECRET") {
+		t.Error("mask modes all and none are not applied")
+	}
+
+	if _, err := newEnvMasker("some", nil); err == nil {
+		t.Error("invalid mode is accepted")
+	}
+
+	if _, err := newEnvMasker("secrets", []string{"["}); err == nil {
+		t.Error("invalid pattern is accepted")
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// An exportFormat is a file format log events are exported to.
+type exportFormat string
+
+const (
+	exportText  exportFormat = "text"
+	exportJSONL exportFormat = "jsonl"
+	exportCSV   exportFormat = "csv"
+)
+
+// An export request writes all events of a log stream to a file. progress
+// counts the events written so far.
+type exportReq struct {
+	region    string
+	logGroup  string
+	logStream string
+	path      string
+	format    exportFormat
+	progress  *atomic.Int64
+}
+
+type exportMsg struct {
+	path   string
+	events int64
+}
+
+// exportTimeFormat is used for the timestamps of exported events.
+const exportTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// exportFormatFor derives the export format from the extension of a file.
+func exportFormatFor(path string) exportFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json", ".ndjson":
+		return exportJSONL
+	case ".csv":
+		return exportCSV
+	default:
+		return exportText
+	}
+}
+
+// A logEventWriter writes log events in an export format.
+type logEventWriter interface {
+	write(event types.OutputLogEvent) error
+	flush() error
+}
+
+func newLogEventWriter(w io.Writer, format exportFormat) logEventWriter {
+	switch format {
+	case exportJSONL:
+		return jsonlLogEventWriter{json.NewEncoder(w)}
+	case exportCSV:
+		return &csvLogEventWriter{w: csv.NewWriter(w)}
+	default:
+		return textLogEventWriter{w}
+	}
+}
+
+func exportTime(millis *int64) string {
+	if millis == nil {
+		return ""
+	}
+
+	return time.UnixMilli(*millis).UTC().Format(exportTimeFormat)
+}
+
+type textLogEventWriter struct {
+	w io.Writer
+}
+
+func (t textLogEventWriter) write(event types.OutputLogEvent) error {
+	_, err := fmt.Fprintf(
+		t.w,
+		"%s\t%s\t%s\n",
+		exportTime(event.Timestamp),
+		exportTime(event.IngestionTime),
+		strings.TrimSuffix(deref(event.Message), "\n"),
+	)
+
+	return err
+}
+
+func (t textLogEventWriter) flush() error {
+	return nil
+}
+
+type jsonlLogEventWriter struct {
+	enc *json.Encoder
+}
+
+func (j jsonlLogEventWriter) write(event types.OutputLogEvent) error {
+	return j.enc.Encode(struct {
+		Timestamp     string `json:"timestamp"`
+		IngestionTime string `json:"ingestionTime"`
+		Message       string `json:"message"`
+	}{
+		exportTime(event.Timestamp),
+		exportTime(event.IngestionTime),
+		strings.TrimSuffix(deref(event.Message), "\n"),
+	})
+}
+
+func (j jsonlLogEventWriter) flush() error {
+	return nil
+}
+
+type csvLogEventWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvLogEventWriter) write(event types.OutputLogEvent) error {
+	if !c.headerWritten {
+		c.headerWritten = true
+		if err := c.w.Write([]string{"timestamp", "ingestion_time", "message"}); err != nil {
+			return err
+		}
+	}
+
+	return c.w.Write([]string{
+		exportTime(event.Timestamp),
+		exportTime(event.IngestionTime),
+		strings.TrimSuffix(deref(event.Message), "\n"),
+	})
+}
+
+func (c *csvLogEventWriter) flush() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// exportLogEvents writes all events of the requested log stream to a file,
+// which is only created once all events have been written.
+func exportLogEvents(ctx context.Context, c cloudwatchLogsAPI, req exportReq) (err error) {
+	path, err := expandHome(req.path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	w := newLogEventWriter(f, req.format)
+	err = forEachLogEvent(ctx, c, req.logGroup, req.logStream, func(event types.OutputLogEvent) error {
+		if err := w.write(event); err != nil {
+			return err
+		}
+
+		req.progress.Add(1)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = w.flush(); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// defaultExportPath derives a file name from the name of a log stream.
+func defaultExportPath(logStream string) string {
+	return strings.NewReplacer("/", "-", "[", "", "]", "-", "$", "").Replace(logStream) + ".log"
+}
+
+func newExportInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "export to: "
+	t.Placeholder = "file"
+
+	return t
+}
+
+func (m model) viewExportUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.exporting = false
+			m.exportInput.Blur()
+
+			return m, nil
+		case key.Matches(msg, m.keys.choose):
+			path := strings.TrimSpace(m.exportInput.Value())
+			if path == "" {
+				return m, nil
+			}
+
+			progress := &atomic.Int64{}
+
+			select {
+			case m.reqCh <- m.tag(exportReq{
+				region:    m.activeLogRegion,
+				logGroup:  m.activeLogGroup,
+				logStream: m.activeLogStream,
+				path:      path,
+				format:    exportFormatFor(path),
+				progress:  progress,
+			}):
+				m.stopFollow()
+				m.exporting = false
+				m.exportInput.Blur()
+				cmd = m.startLoading()
+				m.exportProgress = progress
+			default:
+			}
+
+			return m, cmd
+		}
+	}
+
+	m.exportInput, cmd = m.exportInput.Update(msg)
+
+	return m, cmd
+}
+
+func (m *model) onRcvExportMsg(msg exportMsg) {
+	m.exportProgress = nil
+	m.exportStatus = fmt.Sprintf("exported %d events to %s", msg.events, msg.path)
+	m.loading = false
+}
+
+func (m model) exportPromptView() string {
+	return m.exportInput.View() + helpStyle.Render(fmt.Sprintf("format: %s • %s • %s", exportFormatFor(m.exportInput.Value()), keyHelp(m.keys.choose, "export"), keyHelp(m.keys.back, "cancel")))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+func TestLogEventWriters(t *testing.T) {
+	event := types.OutputLogEvent{
+		Timestamp:     ptr(int64(1725192000123)),
+		IngestionTime: ptr(int64(1725192001000)),
+		Message:       ptr("said \"hi\", then left\n"),
+	}
+
+	tests := []struct {
+		format exportFormat
+		want   string
+	}{
+		{exportText, "2024-09-01T12:00:00.123Z\t2024-09-01T12:00:01.000Z\tsaid \"hi\", then left\n"},
+		{exportJSONL, `{"timestamp":"2024-09-01T12:00:00.123Z","ingestionTime":"2024-09-01T12:00:01.000Z","message":"said \"hi\", then left"}` + "\n"},
+		{exportCSV, "timestamp,ingestion_time,message\n2024-09-01T12:00:00.123Z,2024-09-01T12:00:01.000Z,\"said \"\"hi\"\", then left\"\n"},
+	}
+
+	for _, test := range tests {
+		var b strings.Builder
+		w := newLogEventWriter(&b, test.format)
+		if err := w.write(event); err != nil {
+			t.Fatalf("%s: writing: %v", test.format, err)
+		}
+
+		if err := w.flush(); err != nil {
+			t.Fatalf("%s: flushing: %v", test.format, err)
+		}
+
+		if b.String() != test.want {
+			t.Errorf("%s: got %q, want %q", test.format, b.String(), test.want)
+		}
+	}
+}
+
+func TestExportFormatFor(t *testing.T) {
+	tests := map[string]exportFormat{
+		"events.log":   exportText,
+		"events":       exportText,
+		"events.JSONL": exportJSONL,
+		"events.json":  exportJSONL,
+		"events.csv":   exportCSV,
+	}
+
+	for path, want := range tests {
+		if got := exportFormatFor(path); got != want {
+			t.Errorf("%s: got %s, want %s", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,719 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// fakeBackend is an in-memory implementation of lambdaAPI and
+// cloudwatchLogsAPI serving scripted functions, log streams and events.
+type fakeBackend struct {
+	region    string
+	functions []fakeFunction
+	// streams maps log group names to their streams.
+	streams map[string][]fakeStream
+	// pageSize limits the number of functions, log streams and log events
+	// per page.
+	pageSize int
+	// calls counts the API calls by operation name.
+	calls map[string]int
+	// queries maps started query ids to their log group.
+	queries map[string]string
+	// queryPolls is the number of polls a query keeps running for.
+	queryPolls int
+	// metrics maps metric names to their values for consecutive periods
+	// from the start of a GetMetricData request on.
+	metrics map[string][]float64
+	// errs maps operation names to the error they fail with.
+	errs map[string]error
+}
+
+type fakeFunction struct {
+	name    string
+	runtime lambdatypes.Runtime
+	memory  int32
+	timeout int32
+	env     map[string]string
+	tags    map[string]string
+	// versions are the published versions besides $LATEST.
+	versions []string
+	aliases  []fakeAlias
+	// eventSources are the ARNs of the sources of event source mappings.
+	eventSources []string
+	policy       string
+	reserved     *int32
+	// provisioned maps versions and aliases to their provisioned
+	// concurrency, which is allocated immediately.
+	provisioned map[string]int32
+	// revision counts the configuration updates of the function.
+	revision int
+}
+
+type fakeAlias struct {
+	name    string
+	version string
+	// weights maps additional versions to their routing weight.
+	weights map[string]float64
+}
+
+type fakeStream struct {
+	name   string
+	events []fakeEvent
+}
+
+type fakeEvent struct {
+	timestamp int64
+	message   string
+}
+
+func newFakeBackend(region string) *fakeBackend {
+	return &fakeBackend{
+		region:   region,
+		streams:  map[string][]fakeStream{},
+		pageSize: 50,
+		calls:    map[string]int{},
+		queries:  map[string]string{},
+		metrics:  map[string][]float64{},
+		errs:     map[string]error{},
+	}
+}
+
+func (f *fakeBackend) session() session {
+	return session{
+		regionClients: f.clients(),
+		profile:       "test",
+		region:        f.region,
+		accountId:     "123456789012",
+		forRegion: func(region string) regionClients {
+			return newFakeBackend(region).clients()
+		},
+	}
+}
+
+func (f *fakeBackend) clients() regionClients {
+	return regionClients{lambda: f, cloudwatch: f, metrics: f}
+}
+
+func (f *fakeBackend) addFunction(fn fakeFunction) {
+	f.functions = append(f.functions, fn)
+}
+
+func (f *fakeBackend) addStream(logGroup string, stream fakeStream) {
+	f.streams[logGroup] = append(f.streams[logGroup], stream)
+}
+
+// appendEvents adds events to the end of an existing stream.
+func (f *fakeBackend) appendEvents(logGroup string, streamName string, events ...fakeEvent) {
+	for i, stream := range f.streams[logGroup] {
+		if stream.name == streamName {
+			f.streams[logGroup][i].events = append(stream.events, events...)
+		}
+	}
+}
+
+func fakeLogGroup(name string) string {
+	return "/aws/lambda/" + name
+}
+
+func fakeRevision(revision int) string {
+	return fmt.Sprintf("rev-%d", revision)
+}
+
+func (f *fakeBackend) arn(name string) string {
+	return fmt.Sprintf("arn:aws:lambda:%s:123456789012:function:%s", f.region, name)
+}
+
+func (f *fakeBackend) findFunction(name string) (fakeFunction, error) {
+	for _, fn := range f.functions {
+		if fn.name == name {
+			return fn, nil
+		}
+	}
+
+	return fakeFunction{}, fmt.Errorf("ResourceNotFoundException: function not found: %s", name)
+}
+
+func (f *fakeBackend) ListFunctions(_ context.Context, params *lambda.ListFunctionsInput, _ ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	f.calls["ListFunctions"]++
+	if err := f.errs["ListFunctions"]; err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if params != nil && params.Marker != nil {
+		start, _ = strconv.Atoi(*params.Marker)
+	}
+
+	end := min(start+f.pageSize, len(f.functions))
+	out := &lambda.ListFunctionsOutput{}
+
+	for _, fn := range f.functions[start:end] {
+		out.Functions = append(out.Functions, lambdatypes.FunctionConfiguration{
+			FunctionName:  ptr(fn.name),
+			FunctionArn:   ptr(f.arn(fn.name)),
+			LoggingConfig: &lambdatypes.LoggingConfig{LogGroup: ptr(fakeLogGroup(fn.name))},
+		})
+	}
+
+	if end < len(f.functions) {
+		out.NextMarker = ptr(strconv.Itoa(end))
+	}
+
+	return out, nil
+}
+
+func (f *fakeBackend) GetFunction(_ context.Context, params *lambda.GetFunctionInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	f.calls["GetFunction"]++
+	if err := f.errs["GetFunction"]; err != nil {
+		return nil, err
+	}
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	arn, version := f.arn(fn.name), "$LATEST"
+	if params.Qualifier != nil {
+		if version, err = fn.resolve(*params.Qualifier); err != nil {
+			return nil, err
+		}
+
+		arn += ":" + *params.Qualifier
+	}
+
+	return &lambda.GetFunctionOutput{
+		Configuration: &lambdatypes.FunctionConfiguration{
+			FunctionName:     ptr(fn.name),
+			FunctionArn:      ptr(arn),
+			Version:          ptr(version),
+			CodeSha256:       ptr("sha-" + version),
+			LastUpdateStatus: lambdatypes.LastUpdateStatusSuccessful,
+			Runtime:          fn.runtime,
+			MemorySize:       ptr(fn.memory),
+			Timeout:          ptr(fn.timeout),
+			Architectures:    []lambdatypes.Architecture{lambdatypes.ArchitectureArm64},
+			LastModified:     ptr("2024-09-01T12:00:00.000+0000"),
+			RevisionId:       ptr(fakeRevision(fn.revision)),
+			Environment:      &lambdatypes.EnvironmentResponse{Variables: fn.env},
+			LoggingConfig:    &lambdatypes.LoggingConfig{LogGroup: ptr(fakeLogGroup(fn.name))},
+		},
+		Concurrency: &lambdatypes.Concurrency{ReservedConcurrentExecutions: fn.reserved},
+		Tags:        fn.tags,
+	}, nil
+}
+
+// Invoke echoes the payload and reports the invocation in the tail log.
+func (f *fakeBackend) Invoke(_ context.Context, params *lambda.InvokeInput, _ ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	f.calls["Invoke"]++
+
+	if _, err := f.findFunction(*params.FunctionName); err != nil {
+		return nil, err
+	}
+
+	logResult := base64.StdEncoding.EncodeToString([]byte("START RequestId: fake\nEND RequestId: fake\n"))
+
+	return &lambda.InvokeOutput{
+		StatusCode:      200,
+		ExecutedVersion: ptr("$LATEST"),
+		Payload:         params.Payload,
+		LogResult:       &logResult,
+	}, nil
+}
+
+func (f *fakeBackend) DescribeLogGroups(_ context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	f.calls["DescribeLogGroups"]++
+
+	names := make([]string, 0, len(f.streams))
+	for name := range f.streams {
+		if strings.HasPrefix(name, *params.LogGroupNamePrefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	out := &cloudwatchlogs.DescribeLogGroupsOutput{}
+	for _, name := range names {
+		out.LogGroups = append(out.LogGroups, types.LogGroup{LogGroupName: ptr(name)})
+	}
+
+	return out, nil
+}
+
+// DescribeLogStreams returns all streams of a log group in one page, ordered
+// by their last event.
+func (f *fakeBackend) DescribeLogStreams(_ context.Context, params *cloudwatchlogs.DescribeLogStreamsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	f.calls["DescribeLogStreams"]++
+
+	streams, ok := f.streams[*params.LogGroupName]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: log group not found: %s", *params.LogGroupName)
+	}
+
+	out := &cloudwatchlogs.DescribeLogStreamsOutput{}
+	for _, stream := range streams {
+		logStream := types.LogStream{LogStreamName: ptr(stream.name)}
+		if len(stream.events) > 0 {
+			logStream.LastEventTimestamp = ptr(stream.events[len(stream.events)-1].timestamp)
+		}
+
+		out.LogStreams = append(out.LogStreams, logStream)
+	}
+
+	sort.SliceStable(out.LogStreams, func(i, j int) bool {
+		a, b := out.LogStreams[i].LastEventTimestamp, out.LogStreams[j].LastEventTimestamp
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+
+		return *a > *b
+	})
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+
+	end := min(start+f.pageSize, len(out.LogStreams))
+	if end < len(out.LogStreams) {
+		out.NextToken = ptr(strconv.Itoa(end))
+	}
+	out.LogStreams = out.LogStreams[start:end]
+
+	return out, nil
+}
+
+// GetLogEvents returns the most recent page of events of a stream, or the
+// first one if StartFromHead is set, unless a token is given. Forward tokens
+// are of the form "f/<index>" and return the page of events from that index
+// on, backward tokens of the form "b/<index>" return the page of events before
+// that index. Events before StartTime are omitted.
+func (f *fakeBackend) GetLogEvents(_ context.Context, params *cloudwatchlogs.GetLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	f.calls["GetLogEvents"]++
+
+	var stream *fakeStream
+	for i := range f.streams[*params.LogGroupName] {
+		if f.streams[*params.LogGroupName][i].name == *params.LogStreamName {
+			stream = &f.streams[*params.LogGroupName][i]
+		}
+	}
+
+	if stream == nil {
+		return nil, fmt.Errorf("ResourceNotFoundException: log stream not found: %s", *params.LogStreamName)
+	}
+
+	first := 0
+	if params.StartTime != nil {
+		first = sort.Search(len(stream.events), func(i int) bool {
+			return stream.events[i].timestamp >= *params.StartTime
+		})
+	}
+
+	start, end := max(len(stream.events)-f.pageSize, first), len(stream.events)
+	if params.NextToken == nil && deref(params.StartFromHead) {
+		start, end = first, min(first+f.pageSize, len(stream.events))
+	}
+
+	if params.NextToken != nil {
+		if index, ok := strings.CutPrefix(*params.NextToken, "f/"); ok {
+			start, _ = strconv.Atoi(index)
+			end = min(start+f.pageSize, len(stream.events))
+		} else {
+			// Like CloudWatch, the token is returned again once the start of
+			// the stream has been reached.
+			index, _ = strings.CutPrefix(*params.NextToken, "b/")
+			end, _ = strconv.Atoi(index)
+			start = max(end-f.pageSize, first)
+		}
+	}
+
+	out := &cloudwatchlogs.GetLogEventsOutput{
+		NextBackwardToken: ptr(fmt.Sprintf("b/%d", start)),
+		NextForwardToken:  ptr(fmt.Sprintf("f/%d", end)),
+	}
+
+	for _, event := range stream.events[start:end] {
+		out.Events = append(out.Events, types.OutputLogEvent{
+			Timestamp:     ptr(event.timestamp),
+			IngestionTime: ptr(event.timestamp + 1000),
+			Message:       ptr(event.message + "\n"),
+		})
+	}
+
+	return out, nil
+}
+
+// FilterLogEvents returns the events between StartTime and EndTime that
+// contain the filter pattern, without its quotes. Like CloudWatch, pages are
+// not in chronological order across streams, and the last page of events is
+// followed by an empty one. Tokens are the offset of the next page.
+func (f *fakeBackend) FilterLogEvents(_ context.Context, params *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	f.calls["FilterLogEvents"]++
+
+	streams, ok := f.streams[*params.LogGroupName]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: log group not found: %s", *params.LogGroupName)
+	}
+
+	pattern := strings.Trim(deref(params.FilterPattern), `"`)
+	matches := make([]types.FilteredLogEvent, 0)
+	for _, stream := range streams {
+		for _, event := range stream.events {
+			if event.timestamp < *params.StartTime || event.timestamp > *params.EndTime || !strings.Contains(event.message, pattern) {
+				continue
+			}
+
+			matches = append(matches, types.FilteredLogEvent{
+				LogStreamName: ptr(stream.name),
+				Timestamp:     ptr(event.timestamp),
+				Message:       ptr(event.message + "\n"),
+			})
+		}
+	}
+
+	start := 0
+	if params.NextToken != nil {
+		start, _ = strconv.Atoi(*params.NextToken)
+	}
+
+	end := min(start+f.pageSize, len(matches))
+	if params.Limit != nil {
+		end = min(end, start+int(*params.Limit))
+	}
+
+	out := &cloudwatchlogs.FilterLogEventsOutput{Events: matches[start:end]}
+	if end > start {
+		out.NextToken = ptr(strconv.Itoa(end))
+	}
+
+	return out, nil
+}
+
+func (f *fakeBackend) StartQuery(_ context.Context, params *cloudwatchlogs.StartQueryInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+	f.calls["StartQuery"]++
+
+	if _, ok := f.streams[*params.LogGroupName]; !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: log group not found: %s", *params.LogGroupName)
+	}
+
+	id := fmt.Sprintf("query-%d", len(f.queries))
+	f.queries[id] = *params.LogGroupName
+
+	return &cloudwatchlogs.StartQueryOutput{QueryId: &id}, nil
+}
+
+// GetQueryResults reports queries as running for the first queryPolls polls and
+// then returns the timestamp, stream and message of every event in the log
+// group.
+func (f *fakeBackend) GetQueryResults(_ context.Context, params *cloudwatchlogs.GetQueryResultsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	f.calls["GetQueryResults"]++
+
+	logGroup, ok := f.queries[*params.QueryId]
+	if !ok {
+		return nil, fmt.Errorf("ResourceNotFoundException: query not found: %s", *params.QueryId)
+	}
+
+	if f.calls["GetQueryResults"] <= f.queryPolls {
+		return &cloudwatchlogs.GetQueryResultsOutput{Status: types.QueryStatusRunning}, nil
+	}
+
+	out := &cloudwatchlogs.GetQueryResultsOutput{
+		Status:     types.QueryStatusComplete,
+		Statistics: &types.QueryStatistics{},
+	}
+
+	for _, stream := range f.streams[logGroup] {
+		for _, event := range stream.events {
+			out.Results = append(out.Results, []types.ResultField{
+				{Field: ptr("@timestamp"), Value: ptr(time.UnixMilli(event.timestamp).UTC().Format("2006-01-02 15:04:05.000"))},
+				{Field: ptr("@logStream"), Value: ptr(stream.name)},
+				{Field: ptr("@message"), Value: ptr(event.message)},
+				{Field: ptr("@ptr"), Value: ptr("opaque")},
+			})
+			out.Statistics.RecordsMatched++
+		}
+	}
+	out.Statistics.RecordsScanned = out.Statistics.RecordsMatched
+
+	return out, nil
+}
+
+// resolve returns the version a qualifier refers to.
+func (fn fakeFunction) resolve(qualifier string) (string, error) {
+	if qualifier == "$LATEST" || slices.Contains(fn.versions, qualifier) {
+		return qualifier, nil
+	}
+
+	for _, alias := range fn.aliases {
+		if alias.name == qualifier {
+			return alias.version, nil
+		}
+	}
+
+	return "", fmt.Errorf("ResourceNotFoundException: qualifier not found: %s", qualifier)
+}
+
+func (f *fakeBackend) ListVersionsByFunction(_ context.Context, params *lambda.ListVersionsByFunctionInput, _ ...func(*lambda.Options)) (*lambda.ListVersionsByFunctionOutput, error) {
+	f.calls["ListVersionsByFunction"]++
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &lambda.ListVersionsByFunctionOutput{}
+	for _, version := range append([]string{"$LATEST"}, fn.versions...) {
+		out.Versions = append(out.Versions, lambdatypes.FunctionConfiguration{
+			FunctionName: ptr(fn.name),
+			Version:      ptr(version),
+			Description:  ptr("release " + version),
+			CodeSha256:   ptr("sha-" + version),
+			LastModified: ptr("2024-09-01T12:00:00.000+0000"),
+		})
+	}
+
+	return out, nil
+}
+
+func (f *fakeBackend) ListAliases(_ context.Context, params *lambda.ListAliasesInput, _ ...func(*lambda.Options)) (*lambda.ListAliasesOutput, error) {
+	f.calls["ListAliases"]++
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &lambda.ListAliasesOutput{}
+	for _, alias := range fn.aliases {
+		config := lambdatypes.AliasConfiguration{
+			Name:            ptr(alias.name),
+			FunctionVersion: ptr(alias.version),
+		}
+		if len(alias.weights) > 0 {
+			config.RoutingConfig = &lambdatypes.AliasRoutingConfiguration{AdditionalVersionWeights: alias.weights}
+		}
+
+		out.Aliases = append(out.Aliases, config)
+	}
+
+	return out, nil
+}
+
+// ListEventSourceMappings returns one enabled SQS-style mapping per event
+// source of the function, paginated by pageSize.
+func (f *fakeBackend) ListEventSourceMappings(_ context.Context, params *lambda.ListEventSourceMappingsInput, _ ...func(*lambda.Options)) (*lambda.ListEventSourceMappingsOutput, error) {
+	f.calls["ListEventSourceMappings"]++
+
+	name, _, _ := strings.Cut(*params.FunctionName, ":")
+	fn, err := f.findFunction(name)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if params.Marker != nil {
+		start, _ = strconv.Atoi(*params.Marker)
+	}
+	end := min(start+f.pageSize, len(fn.eventSources))
+
+	out := &lambda.ListEventSourceMappingsOutput{}
+	for _, arn := range fn.eventSources[start:end] {
+		out.EventSourceMappings = append(out.EventSourceMappings, lambdatypes.EventSourceMappingConfiguration{
+			EventSourceArn:       ptr(arn),
+			BatchSize:            ptr(int32(10)),
+			State:                ptr("Enabled"),
+			LastProcessingResult: ptr("OK"),
+		})
+	}
+
+	if end < len(fn.eventSources) {
+		out.NextMarker = ptr(strconv.Itoa(end))
+	}
+
+	return out, nil
+}
+
+func (f *fakeBackend) GetPolicy(_ context.Context, params *lambda.GetPolicyInput, _ ...func(*lambda.Options)) (*lambda.GetPolicyOutput, error) {
+	f.calls["GetPolicy"]++
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if fn.policy == "" {
+		return nil, &lambdatypes.ResourceNotFoundException{Message: ptr("The resource you requested does not exist.")}
+	}
+
+	return &lambda.GetPolicyOutput{Policy: ptr(fn.policy)}, nil
+}
+
+func (f *fakeBackend) functionIndex(name string) (int, error) {
+	for i, fn := range f.functions {
+		if fn.name == name {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("ResourceNotFoundException: function not found: %s", name)
+}
+
+func (f *fakeBackend) GetFunctionConcurrency(_ context.Context, params *lambda.GetFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionConcurrencyOutput, error) {
+	f.calls["GetFunctionConcurrency"]++
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lambda.GetFunctionConcurrencyOutput{ReservedConcurrentExecutions: fn.reserved}, nil
+}
+
+func (f *fakeBackend) PutFunctionConcurrency(_ context.Context, params *lambda.PutFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.PutFunctionConcurrencyOutput, error) {
+	f.calls["PutFunctionConcurrency"]++
+
+	i, err := f.functionIndex(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	f.functions[i].reserved = params.ReservedConcurrentExecutions
+
+	return &lambda.PutFunctionConcurrencyOutput{ReservedConcurrentExecutions: params.ReservedConcurrentExecutions}, nil
+}
+
+func (f *fakeBackend) DeleteFunctionConcurrency(_ context.Context, params *lambda.DeleteFunctionConcurrencyInput, _ ...func(*lambda.Options)) (*lambda.DeleteFunctionConcurrencyOutput, error) {
+	f.calls["DeleteFunctionConcurrency"]++
+
+	i, err := f.functionIndex(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	f.functions[i].reserved = nil
+
+	return &lambda.DeleteFunctionConcurrencyOutput{}, nil
+}
+
+func (f *fakeBackend) ListProvisionedConcurrencyConfigs(_ context.Context, params *lambda.ListProvisionedConcurrencyConfigsInput, _ ...func(*lambda.Options)) (*lambda.ListProvisionedConcurrencyConfigsOutput, error) {
+	f.calls["ListProvisionedConcurrencyConfigs"]++
+
+	fn, err := f.findFunction(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &lambda.ListProvisionedConcurrencyConfigsOutput{}
+	for qualifier, value := range fn.provisioned {
+		out.ProvisionedConcurrencyConfigs = append(out.ProvisionedConcurrencyConfigs, lambdatypes.ProvisionedConcurrencyConfigListItem{
+			FunctionArn:                              ptr(f.arn(fn.name) + ":" + qualifier),
+			RequestedProvisionedConcurrentExecutions: ptr(value),
+			AllocatedProvisionedConcurrentExecutions: ptr(value),
+			AvailableProvisionedConcurrentExecutions: ptr(value),
+			Status:                                   lambdatypes.ProvisionedConcurrencyStatusEnumReady,
+		})
+	}
+
+	return out, nil
+}
+
+func (f *fakeBackend) PutProvisionedConcurrencyConfig(_ context.Context, params *lambda.PutProvisionedConcurrencyConfigInput, _ ...func(*lambda.Options)) (*lambda.PutProvisionedConcurrencyConfigOutput, error) {
+	f.calls["PutProvisionedConcurrencyConfig"]++
+
+	i, err := f.functionIndex(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := f.functions[i].resolve(*params.Qualifier); err != nil || *params.Qualifier == "$LATEST" {
+		return nil, fmt.Errorf("ResourceNotFoundException: qualifier not found: %s", *params.Qualifier)
+	}
+
+	if f.functions[i].provisioned == nil {
+		f.functions[i].provisioned = map[string]int32{}
+	}
+	f.functions[i].provisioned[*params.Qualifier] = *params.ProvisionedConcurrentExecutions
+
+	return &lambda.PutProvisionedConcurrencyConfigOutput{Status: lambdatypes.ProvisionedConcurrencyStatusEnumInProgress}, nil
+}
+
+func (f *fakeBackend) DeleteProvisionedConcurrencyConfig(_ context.Context, params *lambda.DeleteProvisionedConcurrencyConfigInput, _ ...func(*lambda.Options)) (*lambda.DeleteProvisionedConcurrencyConfigOutput, error) {
+	f.calls["DeleteProvisionedConcurrencyConfig"]++
+
+	i, err := f.functionIndex(*params.FunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(f.functions[i].provisioned, *params.Qualifier)
+
+	return &lambda.DeleteProvisionedConcurrencyConfigOutput{}, nil
+}
+
+func (f *fakeBackend) GetMetricData(_ context.Context, params *cloudwatch.GetMetricDataInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.GetMetricDataOutput, error) {
+	f.calls["GetMetricData"]++
+
+	out := &cloudwatch.GetMetricDataOutput{}
+	for _, query := range params.MetricDataQueries {
+		period := time.Duration(*query.MetricStat.Period) * time.Second
+		result := cloudwatchtypes.MetricDataResult{Id: query.Id}
+
+		// Series of a statistic, e.g. "Duration p99", take precedence.
+		values, ok := f.metrics[*query.MetricStat.Metric.MetricName+" "+*query.MetricStat.Stat]
+		if !ok {
+			values = f.metrics[*query.MetricStat.Metric.MetricName]
+		}
+
+		for i, v := range values {
+			result.Timestamps = append(result.Timestamps, params.StartTime.Add(time.Duration(i)*period))
+			result.Values = append(result.Values, v)
+		}
+
+		out.MetricDataResults = append(out.MetricDataResults, result)
+	}
+
+	return out, nil
+}
+
+// UpdateFunctionConfiguration replaces the environment of a function if the
+// given revision is its current one. The update completes once the function
+// is fetched again.
+func (f *fakeBackend) UpdateFunctionConfiguration(_ context.Context, params *lambda.UpdateFunctionConfigurationInput, _ ...func(*lambda.Options)) (*lambda.UpdateFunctionConfigurationOutput, error) {
+	f.calls["UpdateFunctionConfiguration"]++
+
+	for i, fn := range f.functions {
+		if fn.name != *params.FunctionName {
+			continue
+		}
+
+		if params.RevisionId != nil && *params.RevisionId != fakeRevision(fn.revision) {
+			return nil, &lambdatypes.PreconditionFailedException{Message: ptr("revision mismatch")}
+		}
+
+		if params.Environment != nil {
+			f.functions[i].env = params.Environment.Variables
+		}
+
+		f.functions[i].revision++
+
+		return &lambda.UpdateFunctionConfigurationOutput{
+			FunctionName:     params.FunctionName,
+			LastUpdateStatus: lambdatypes.LastUpdateStatusInProgress,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("ResourceNotFoundException: function not found: %s", *params.FunctionName)
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type filterReq struct {
+	region   string
+	logGroup string
+	pattern  string
+	start    time.Time
+	end      time.Time
+}
+
+// A filter message holds the matched events as rows of timestamp, stream and
+// message in chronological order.
+type filterMsg struct {
+	events    [][]string
+	truncated bool
+}
+
+// filterLimit is the maximum number of events a search shows, which are the
+// newest matching events.
+const filterLimit = 1000
+
+// The inputs of the log group search form.
+const (
+	filterInputFrom = iota
+	filterInputTo
+	filterInputPattern
+)
+
+// filterTimeLayouts are the accepted layouts of absolute times, which are
+// interpreted in the local time zone.
+var filterTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var filterStreamStyle = logEventStyle.Padding(1)
+
+// parseFilterTime parses a time relative to now, e.g. "15m", "last 3d", or an
+// absolute time like "2024-09-01 10:00". An empty time refers to now.
+func parseFilterTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "last "))
+	if s == "" || s == "now" {
+		return now, nil
+	}
+
+	// Durations have no unit for days.
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, expected a duration like 15m or 3d or a time like 2024-09-01 10:00", s)
+}
+
+// openFilter opens the search form for the active log group.
+func (m *model) openFilter() tea.Cmd {
+	m.filterFocus = filterInputFrom
+	for i := range m.filterInputs {
+		m.filterInputs[i].Blur()
+	}
+
+	m.activeView = viewFilter
+
+	return m.filterInputs[m.filterFocus].Focus()
+}
+
+func (m model) viewFilterUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.filterInputs[m.filterFocus].Blur()
+			m.activeView = viewLogStream
+
+			return m, nil
+		case key.Matches(msg, m.keys.next, m.keys.prev):
+			m.filterInputs[m.filterFocus].Blur()
+			if key.Matches(msg, m.keys.next) {
+				m.filterFocus = (m.filterFocus + 1) % len(m.filterInputs)
+			} else {
+				m.filterFocus = (m.filterFocus + len(m.filterInputs) - 1) % len(m.filterInputs)
+			}
+
+			return m, m.filterInputs[m.filterFocus].Focus()
+		case key.Matches(msg, m.keys.choose):
+			return m, m.runFilter()
+		}
+	}
+
+	m.filterInputs[m.filterFocus], cmd = m.filterInputs[m.filterFocus].Update(msg)
+
+	return m, cmd
+}
+
+// runFilter searches the active log group with the entered time range and
+// filter pattern.
+func (m *model) runFilter() tea.Cmd {
+	now := time.Now()
+
+	start, err := parseFilterTime(m.filterInputs[filterInputFrom].Value(), now)
+	end := now
+	if err == nil {
+		end, err = parseFilterTime(m.filterInputs[filterInputTo].Value(), now)
+	}
+
+	if err == nil && !start.Before(end) {
+		err = fmt.Errorf("the start %s is not before the end %s", start.Format(time.RFC1123), end.Format(time.RFC1123))
+	}
+
+	if err != nil {
+		m.showError(err.Error())
+
+		return nil
+	}
+
+	select {
+	case m.reqCh <- m.tag(filterReq{
+		region:   m.activeLogRegion,
+		logGroup: m.activeLogGroup,
+		pattern:  strings.TrimSpace(m.filterInputs[filterInputPattern].Value()),
+		start:    start,
+		end:      end,
+	}):
+		m.filterStart, m.filterEnd = start, end
+		m.filterInputs[m.filterFocus].Blur()
+
+		return m.startLoading()
+	default:
+		return nil
+	}
+}
+
+func (m model) viewFilterResultUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.filterOutput, cmd = m.filterOutput.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		m.activeView = viewFilter
+		cmd = m.filterInputs[m.filterFocus].Focus()
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvFilterMsg(msg filterMsg) {
+	m.filterEvents = msg.events
+	m.filterTruncated = msg.truncated
+	m.renderFilterEvents()
+	m.filterOutput.GotoTop()
+	m.activeView = viewFilterResult
+	m.loading = false
+}
+
+// renderFilterEvents renders the matched events into the viewport.
+func (m *model) renderFilterEvents() {
+	lines := make([]string, 0, len(m.filterEvents))
+	for i, row := range m.filterEvents {
+		timestampStyle := logEventTimestampStyle
+		streamStyle := filterStreamStyle
+		messageStyle := logEventStyle
+		if i%2 == 1 {
+			timestampStyle = timestampStyle.Background(activeTheme.Stripe)
+			streamStyle = streamStyle.Background(activeTheme.Stripe)
+			messageStyle = messageStyle.Background(activeTheme.Stripe)
+		}
+
+		timestamp := timestampStyle.Render(row[0])
+		stream := streamStyle.Render(shortLogStreamName(row[1]))
+		if width := m.filterOutput.Width - lipgloss.Width(timestamp) - lipgloss.Width(stream) - logEventStyle.GetHorizontalFrameSize(); width > 0 {
+			messageStyle = messageStyle.Width(width)
+		}
+
+		height := max(lipgloss.Height(timestamp), lipgloss.Height(messageStyle.Render(row[2])))
+
+		lines = append(lines, lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			timestampStyle.Height(height).Render(row[0]),
+			streamStyle.Height(height).Render(shortLogStreamName(row[1])),
+			messageStyle.Height(height).Render(row[2]),
+		))
+	}
+
+	m.filterOutput.SetContent(strings.Join(lines, "\n"))
+}
+
+// shortLogStreamName shortens the name of a Lambda log stream, such as
+// "2024/09/01/[$LATEST]0123456789abcdef", to its version and the start of its
+// id.
+func shortLogStreamName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if version, id, ok := strings.Cut(name, "]"); ok && len(id) > 8 {
+		return version + "]" + id[:8]
+	}
+
+	return name
+}
+
+func (m model) filterView() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Search - Log Group \"%s\" - %s", m.activeLogGroup, m.sessionTitle())),
+		"",
+		m.filterInputs[filterInputFrom].View(),
+		m.filterInputs[filterInputTo].View(),
+		m.filterInputs[filterInputPattern].View(),
+		"",
+		helpStyle.Render("Times are durations like 15m or 3d before now, or local times like 2024-09-01 10:00"),
+		"",
+		helpLine(
+			keyHelp(m.keys.choose, "search"),
+			keyHelp(m.keys.next, "next field"),
+			keyHelp(m.keys.prev, "prev field"),
+			keyHelp(m.keys.back, ""),
+		),
+	)
+}
+
+func (m model) filterResultView() string {
+	help := helpLine(keyHelp(m.keys.back, ""))
+	status := fmt.Sprintf("%d events from %s to %s", len(m.filterEvents), m.filterStart.Format(time.RFC1123), m.filterEnd.Format(time.RFC1123))
+	if m.filterTruncated {
+		status += fmt.Sprintf(" • limited to the newest %d events", filterLimit)
+	}
+
+	gap := m.winWidth - lipgloss.Width(help) - lipgloss.Width(status) - logEventStatusStyle.GetHorizontalFrameSize()
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.filterOutput.View(),
+		logEventStatusStyle.Render(help+strings.Repeat(" ", max(gap, 1))+status),
+	)
+}
+
+// newFilterInputs returns the inputs of the log group search form.
+func newFilterInputs() []textinput.Model {
+	from := textinput.New()
+	from.Prompt = "from:    "
+	from.SetValue("15m")
+
+	to := textinput.New()
+	to.Prompt = "to:      "
+	to.Placeholder = "now"
+
+	pattern := textinput.New()
+	pattern.Prompt = "pattern: "
+	pattern.Placeholder = "CloudWatch filter pattern, e.g. ERROR or { $.level = \"error\" }"
+
+	return []textinput.Model{from, to, pattern}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseFilterTime(t *testing.T) {
+	now := time.Date(2024, 9, 10, 12, 0, 0, 0, time.Local)
+
+	for input, want := range map[string]time.Time{
+		"":                     now,
+		"now":                  now,
+		"15m":                  now.Add(-15 * time.Minute),
+		"last 1h30m":           now.Add(-90 * time.Minute),
+		"3d":                   now.AddDate(0, 0, -3),
+		"2024-09-01":           time.Date(2024, 9, 1, 0, 0, 0, 0, time.Local),
+		"2024-09-01 10:30":     time.Date(2024, 9, 1, 10, 30, 0, 0, time.Local),
+		"2024-09-01T10:30:00Z": time.Date(2024, 9, 1, 10, 30, 0, 0, time.UTC),
+	} {
+		got, err := parseFilterTime(input, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseFilterTime(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"yesterday", "15x", "2024-13-01"} {
+		if _, err := parseFilterTime(input, now); err == nil {
+			t.Errorf("parseFilterTime(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestShortLogStreamName(t *testing.T) {
+	for name, want := range map[string]string{
+		"2024/09/01/[$LATEST]0123456789abcdef": "[$LATEST]01234567",
+		"2024/09/01/[3]abc":                    "[3]abc",
+		"custom-stream":                        "custom-stream",
+	} {
+		if got := shortLogStreamName(name); got != want {
+			t.Errorf("shortLogStreamName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestFilterLogEventsLimit(t *testing.T) {
+	backend := newPagedBackend()
+	logGroup := fakeLogGroup("orders-api")
+
+	events, truncated, err := filterLogEvents(context.Background(), backend, logGroup, "event", time.UnixMilli(0), time.Now(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 3 || !truncated {
+		t.Fatalf("got %d events, truncated %v, want 3 truncated events", len(events), truncated)
+	}
+
+	// The last stream holds the newest events.
+	for i, row := range events {
+		if want := fmt.Sprintf("event %d", i+2); row[1] != "stream-4" || row[2] != want {
+			t.Fatalf("got event %q, want %s of stream-4", row, want)
+		}
+	}
+
+	// Limits reached on the last page with events don't truncate.
+	events, truncated, err = filterLogEvents(context.Background(), backend, logGroup, "event 4", time.UnixMilli(0), time.Now(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 5 || truncated {
+		t.Fatalf("got %d events, truncated %v, want 5 events", len(events), truncated)
+	}
+
+	events, truncated, err = filterLogEvents(context.Background(), backend, logGroup, "event 4", time.UnixMilli(0), time.Now(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 5 || truncated {
+		t.Fatalf("got %d events, truncated %v, want 5 events", len(events), truncated)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const followInterval = 2 * time.Second
+
+type logEventFollowReq struct {
+	region       string
+	logGroup     string
+	logStream    string
+	forwardToken string
+}
+
+type logEventFollowMsg struct {
+	logStream    string
+	events       [][]string
+	forwardToken string
+}
+
+// A follow tick message triggers the next poll for new log events. Ticks
+// belonging to an earlier follow session are identified by their id and
+// dropped.
+type followTickMsg struct {
+	id int
+}
+
+var (
+	followActiveStyle = lipgloss.NewStyle().Bold(true)
+	followPausedStyle = lipgloss.NewStyle().Bold(true)
+)
+
+func (m *model) toggleFollow() tea.Cmd {
+	if m.following {
+		m.stopFollow()
+
+		return nil
+	}
+
+	m.following = true
+	m.followId++
+	m.followAutoScroll = true
+	m.logEvents.GotoBottom()
+
+	return m.followLogEvents()
+}
+
+func (m *model) stopFollow() {
+	m.following = false
+	m.followId++
+}
+
+// resumeFollow restarts polling when returning to the log events, as polling
+// stops while another view is shown. Ticks still pending are dropped so only
+// one poll is scheduled at a time.
+func (m *model) resumeFollow() tea.Cmd {
+	if !m.following {
+		return nil
+	}
+
+	m.followId++
+
+	return m.followLogEvents()
+}
+
+// followLogEvents requests the events ingested since the last poll and
+// schedules the next poll.
+func (m model) followLogEvents() tea.Cmd {
+	if !m.following || m.activeView != viewLogEvent {
+		return nil
+	}
+
+	// Skip this round if the request handler is busy.
+	select {
+	case m.reqCh <- logEventFollowReq{
+		region:       m.activeLogRegion,
+		logGroup:     m.activeLogGroup,
+		logStream:    m.activeLogStream,
+		forwardToken: m.logEventsForwardToken,
+	}:
+	default:
+	}
+
+	id := m.followId
+
+	return tea.Tick(followInterval, func(time.Time) tea.Msg {
+		return followTickMsg{id: id}
+	})
+}
+
+func (m *model) onRcvLogEventFollowMsg(msg logEventFollowMsg) {
+	if !m.following || msg.logStream != m.activeLogStream {
+		return
+	}
+
+	m.logEventsForwardToken = msg.forwardToken
+	if len(msg.events) == 0 {
+		return
+	}
+
+	m.appendLogEvents(msg.events)
+
+	if m.followAutoScroll {
+		m.logEvents.GotoBottom()
+	}
+}
+
+func (m model) followStatusView() string {
+	switch {
+	case !m.following:
+		return ""
+	case m.followAutoScroll:
+		return followActiveStyle.Render("● following")
+	default:
+		return followPausedStyle.Render("● following (paused, scroll to bottom to resume)")
+	}
+}
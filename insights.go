@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+const defaultInsightsQuery = "fields @timestamp, @message\n| sort @timestamp desc\n| limit 100"
+
+type insightsReq struct {
+	region   string
+	logGroup string
+	query    string
+	start    time.Time
+	end      time.Time
+}
+
+type insightsMsg struct {
+	result queryResult
+}
+
+// insightsRanges are the selectable time ranges of a query, relative to the
+// time the query is started.
+var insightsRanges = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"15m", 15 * time.Minute},
+	{"1h", time.Hour},
+	{"3h", 3 * time.Hour},
+	{"12h", 12 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"3d", 3 * 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+}
+
+var (
+	insightsRangeStyle         = lipgloss.NewStyle().Padding(0, 1)
+	insightsSelectedRangeStyle = insightsRangeStyle.Bold(true)
+	insightsHeaderStyle        = lambdaDetailFieldNameStyle
+	insightsCellStyle          = lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1)
+)
+
+func (m model) viewInsightsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.insightsQuery.Blur()
+			m.activeView = viewLambda
+
+			return m, nil
+		case key.Matches(msg, m.keys.next):
+			m.insightsRange = (m.insightsRange + 1) % len(insightsRanges)
+
+			return m, nil
+		case key.Matches(msg, m.keys.prev):
+			m.insightsRange = (m.insightsRange + len(insightsRanges) - 1) % len(insightsRanges)
+
+			return m, nil
+		case key.Matches(msg, m.keys.run):
+			end := time.Now()
+
+			select {
+			case m.reqCh <- m.tag(insightsReq{
+				region:   m.insightsTarget.region,
+				logGroup: m.insightsTarget.logGroup,
+				query:    m.insightsQuery.Value(),
+				start:    end.Add(-insightsRanges[m.insightsRange].duration),
+				end:      end,
+			}):
+				m.insightsQuery.Blur()
+				cmd = m.startLoading()
+			default:
+			}
+
+			return m, cmd
+		}
+	}
+
+	m.insightsQuery, cmd = m.insightsQuery.Update(msg)
+
+	return m, cmd
+}
+
+func (m model) viewInsightsResultUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.insightsOutput, cmd = m.insightsOutput.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		m.activeView = viewInsights
+		cmd = m.insightsQuery.Focus()
+	}
+
+	return m, cmd
+}
+
+func (m model) insightsView() string {
+	ranges := make([]string, 0, len(insightsRanges))
+	for i, r := range insightsRanges {
+		style := insightsRangeStyle
+		if i == m.insightsRange {
+			style = insightsSelectedRangeStyle
+		}
+
+		ranges = append(ranges, style.Render(r.label))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Logs Insights - Log Group \"%s\" - %s", m.insightsTarget.logGroup, m.sessionTitle())),
+		"",
+		m.insightsQuery.View(),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, append([]string{helpStyle.Render("Last ")}, ranges...)...),
+		"",
+		helpLine(
+			keyHelp(m.keys.run, "run query"),
+			keyHelp(m.keys.next, "next range"),
+			keyHelp(m.keys.prev, "prev range"),
+			keyHelp(m.keys.back, ""),
+		),
+	)
+}
+
+func (m *model) onRcvInsightsMsg(msg insightsMsg) {
+	summary := fmt.Sprintf(
+		"%d rows - %.0f records matched - %.0f records scanned - last %s",
+		len(msg.result.rows),
+		msg.result.recordsMatched,
+		msg.result.recordsScanned,
+		insightsRanges[m.insightsRange].label,
+	)
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Logs Insights - Log Group \"%s\"", m.insightsTarget.logGroup)),
+		helpStyle.Render(summary),
+	)
+
+	// Queries without results still show the projected columns.
+	if len(msg.result.columns) > 0 {
+		t := table.
+			New().
+			Border(lipgloss.HiddenBorder()).
+			StyleFunc(insightsTableStyleFunc).
+			Width(m.insightsOutput.Width).
+			Headers(msg.result.columns...).
+			Rows(msg.result.rows...)
+
+		content = lipgloss.JoinVertical(lipgloss.Left, content, t.Render())
+	}
+
+	m.insightsOutput.SetContent(content)
+	m.insightsOutput.GotoTop()
+	m.activeView = viewInsightsResult
+	m.loading = false
+}
+
+func insightsTableStyleFunc(row, col int) lipgloss.Style {
+	switch {
+	case row == 0:
+		return insightsHeaderStyle
+	case row%2 == 0:
+		return insightsCellStyle.Background(activeTheme.Stripe)
+	default:
+		return insightsCellStyle
+	}
+}
+
+// newInsightsQuery returns the text area used to edit Logs Insights queries.
+func newInsightsQuery() textarea.Model {
+	t := textarea.New()
+	t.CharLimit = 0
+	t.MaxHeight = 0
+	t.SetValue(defaultInsightsQuery)
+
+	return t
+}
+
+// queryColumns returns the columns projected by the fields, display and stats
+// commands of a Logs Insights query, named by their alias or expression.
+// Fields add columns, while display and stats replace them.
+func queryColumns(query string) []string {
+	columns := make([]string, 0)
+
+	for _, command := range splitQuery(query, '|') {
+		name, args, _ := strings.Cut(strings.TrimSpace(command), " ")
+
+		switch strings.ToLower(name) {
+		case "fields":
+			for _, expr := range splitQuery(args, ',') {
+				if column := queryColumnName(expr); column != "" && !slices.Contains(columns, column) {
+					columns = append(columns, column)
+				}
+			}
+		case "display", "stats":
+			columns = make([]string, 0)
+			for _, expr := range splitQuery(args, ',') {
+				// Aggregations are followed by their grouping, e.g.
+				// count(*) by bin(5m), which is shown after them.
+				if aggregation, group, ok := cutQueryKeyword(expr, "by"); ok && strings.ToLower(name) == "stats" {
+					columns = append(columns, queryColumnName(aggregation), queryColumnName(group))
+
+					continue
+				}
+
+				if column := queryColumnName(expr); column != "" {
+					columns = append(columns, column)
+				}
+			}
+		}
+	}
+
+	return slices.DeleteFunc(columns, func(column string) bool { return column == "" })
+}
+
+// queryColumnName returns the alias of a projected expression, or the
+// expression itself if it has none.
+func queryColumnName(expr string) string {
+	if _, alias, ok := cutQueryKeyword(expr, "as"); ok {
+		return strings.TrimSpace(alias)
+	}
+
+	return strings.TrimSpace(expr)
+}
+
+// cutQueryKeyword cuts expr around the last occurrence of a keyword outside of
+// parentheses and quotes.
+func cutQueryKeyword(expr string, keyword string) (string, string, bool) {
+	parts := splitQuery(expr, ' ')
+	for i := len(parts) - 1; i > 0; i-- {
+		if strings.EqualFold(parts[i], keyword) {
+			return strings.Join(parts[:i], " "), strings.Join(parts[i+1:], " "), true
+		}
+	}
+
+	return expr, "", false
+}
+
+// splitQuery splits a query at sep outside of parentheses, quotes and regular
+// expressions. Spaces are collapsed when splitting at spaces.
+func splitQuery(query string, sep rune) []string {
+	parts := make([]string, 0)
+	var part strings.Builder
+	var quote rune
+	depth := 0
+	prev := ' '
+
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '/' && startsRegex(prev, part.String()):
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0 || sep == ' ' && unicode.IsSpace(r) && depth == 0:
+			if s := strings.TrimSpace(part.String()); s != "" || sep != ' ' {
+				parts = append(parts, s)
+			}
+			part.Reset()
+			prev = r
+
+			continue
+		}
+
+		part.WriteRune(r)
+		if !unicode.IsSpace(r) {
+			prev = r
+		}
+	}
+
+	if s := strings.TrimSpace(part.String()); s != "" || sep != ' ' {
+		parts = append(parts, s)
+	}
+
+	return parts
+}
+
+// startsRegex reports whether a slash following text starts a regular
+// expression, which follows an operator, a separator or the like and parse
+// keywords, unlike a division.
+func startsRegex(prev rune, text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	return strings.ContainsRune("(,=~", prev) || strings.HasSuffix(text, "like") || strings.HasSuffix(text, "parse")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+func TestQueryColumns(t *testing.T) {
+	for query, want := range map[string][]string{
+		defaultInsightsQuery: {"@timestamp", "@message"},
+		"fields @timestamp, @message | filter @message like /a|b/ | fields strlen(@message) as length":     {"@timestamp", "@message", "length"},
+		"filter @type = \"REPORT\" | stats count(*) as invocations, avg(@duration) by bin(5m), @logStream": {"invocations", "avg(@duration)", "bin(5m)", "@logStream"},
+		"fields @timestamp, @message | display @message":                                                   {"@message"},
+		"stats sum(@billedDuration) / 1000 as seconds":                                                     {"seconds"},
+		"filter @message like \"|\"":                                                                       {},
+	} {
+		if got := queryColumns(query); !slices.Equal(got, want) {
+			t.Errorf("columns of %q: got %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestNewQueryResult(t *testing.T) {
+	field := func(name string, value string) types.ResultField {
+		return types.ResultField{Field: &name, Value: &value}
+	}
+
+	// Columns are those of the query, even without results.
+	result := newQueryResult("stats count(*) as n by @logStream", &cloudwatchlogs.GetQueryResultsOutput{})
+	if !slices.Equal(result.columns, []string{"n", "@logStream"}) || len(result.rows) != 0 {
+		t.Fatalf("got %+v", result)
+	}
+
+	result = newQueryResult("fields @timestamp, @message", &cloudwatchlogs.GetQueryResultsOutput{
+		Results: [][]types.ResultField{
+			{field("@message", "hello\n"), field("@ptr", "x"), field("@timestamp", "t0")},
+			{field("@timestamp", "t1"), field("@extra", "e")},
+		},
+	})
+	if !slices.Equal(result.columns, []string{"@timestamp", "@message", "@extra"}) {
+		t.Fatalf("got columns %q", result.columns)
+	}
+
+	if want := [][]string{{"t0", "hello", ""}, {"t1", "", "e"}}; !slices.EqualFunc(result.rows, want, slices.Equal) {
+		t.Fatalf("got rows %q, want %q", result.rows, want)
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The statuses of an invocation.
+const (
+	invocationOk         = "ok"
+	invocationError      = "error"
+	invocationTimeout    = "timeout"
+	invocationIncomplete = "incomplete"
+)
+
+// invocationErrorMarkers are contained in log lines of failed invocations.
+var invocationErrorMarkers = []string{"\tERROR\t", "[ERROR]", "Runtime exited with error", `"level":"ERROR"`}
+
+// An invocation groups the log events of a single request, from the lines
+// preceding its START line up to its REPORT line.
+type invocation struct {
+	requestId string
+	start     string
+	// first and last are the indexes of the first and last log event of the
+	// invocation.
+	first          int
+	last           int
+	duration       string
+	billedDuration string
+	memorySize     string
+	maxMemoryUsed  string
+	// initDuration is only reported for cold starts.
+	initDuration string
+	status       string
+	// lines are the rendered log events of the invocation, which are copied
+	// as events may be loaded while the invocations are listed.
+	lines []string
+}
+
+func (i invocation) Title() string {
+	return fmt.Sprintf("%s  %s", i.requestId, i.status)
+}
+
+func (i invocation) Description() string {
+	parts := []string{i.start}
+
+	if i.duration != "" {
+		parts = append(parts, "duration "+i.duration, "billed "+i.billedDuration)
+	}
+
+	if i.maxMemoryUsed != "" {
+		parts = append(parts, fmt.Sprintf("memory %s/%s", i.maxMemoryUsed, i.memorySize))
+	}
+
+	if i.initDuration != "" {
+		parts = append(parts, "init "+i.initDuration)
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+func (i invocation) FilterValue() string {
+	return i.requestId
+}
+
+// parseInvocations groups log events into invocations by their START, END and
+// REPORT lines. Lines outside of an invocation belong to the following one,
+// e.g. the INIT_START line of a cold start.
+func parseInvocations(rows [][]string) []invocation {
+	invocations := make([]invocation, 0)
+	current := -1
+	pending := -1
+	failed, timedOut := false, false
+
+	for i, row := range rows {
+		message := row[1]
+
+		if requestId, ok := requestIdOf(message, "START RequestId: "); ok {
+			// Errors of an invocation without a REPORT line are not carried
+			// over, unlike those of the lines preceding the START line.
+			if current >= 0 {
+				invocations[current].status = invocationIncomplete
+				failed, timedOut = false, false
+			}
+
+			first := i
+			if pending >= 0 {
+				first = pending
+			}
+
+			invocations = append(invocations, invocation{requestId: requestId, start: row[0], first: first, last: i})
+			current, pending = len(invocations)-1, -1
+
+			continue
+		}
+
+		if current < 0 && pending < 0 {
+			pending = i
+		}
+
+		if current >= 0 {
+			invocations[current].last = i
+		}
+
+		if strings.Contains(message, "Task timed out after") {
+			timedOut = true
+		} else if slices.ContainsFunc(invocationErrorMarkers, func(marker string) bool { return strings.Contains(message, marker) }) {
+			failed = true
+		}
+
+		requestId, ok := requestIdOf(message, "REPORT RequestId: ")
+		if !ok {
+			continue
+		}
+
+		// The START line of the first invocation may not have been loaded, or
+		// the END and REPORT lines of the current one may be missing.
+		if current >= 0 && invocations[current].requestId != requestId {
+			invocations[current].status = invocationIncomplete
+			invocations[current].last = i - 1
+			current, pending = -1, i
+			failed, timedOut = false, false
+		}
+
+		if current < 0 {
+			invocations = append(invocations, invocation{requestId: requestId, start: rows[pending][0], first: pending, last: i})
+			current = len(invocations) - 1
+		}
+
+		inv := &invocations[current]
+		inv.status = invocationOk
+		if failed {
+			inv.status = invocationError
+		}
+		if timedOut {
+			inv.status = invocationTimeout
+		}
+
+		for _, field := range strings.Split(message, "\t") {
+			name, value, _ := strings.Cut(strings.TrimSpace(field), ": ")
+			switch name {
+			case "Duration":
+				inv.duration = value
+			case "Billed Duration":
+				inv.billedDuration = value
+			case "Memory Size":
+				inv.memorySize = value
+			case "Max Memory Used":
+				inv.maxMemoryUsed = value
+			case "Init Duration":
+				inv.initDuration = value
+			case "Status":
+				// Recent runtimes report the outcome of failed invocations.
+				if value == invocationTimeout || value == invocationError {
+					inv.status = value
+				}
+			}
+		}
+
+		current, pending = -1, -1
+		failed, timedOut = false, false
+	}
+
+	if current >= 0 {
+		invocations[current].status = invocationIncomplete
+	}
+
+	return invocations
+}
+
+// requestIdOf returns the request ID of a line starting with prefix.
+func requestIdOf(message string, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(message, prefix)
+	if !ok {
+		return "", false
+	}
+
+	requestId, _, _ := strings.Cut(rest, " ")
+	requestId, _, _ = strings.Cut(requestId, "\t")
+
+	return strings.TrimSpace(requestId), true
+}
+
+// showInvocations lists the invocations of the loaded log events.
+func (m *model) showInvocations() {
+	invocations := parseInvocations(m.logEventRows)
+	if len(invocations) == 0 {
+		m.showError("No invocations found in the loaded log events")
+
+		return
+	}
+
+	// The most recent invocation is listed first.
+	items := make([]list.Item, 0, len(invocations))
+	for i := len(invocations) - 1; i >= 0; i-- {
+		inv := invocations[i]
+		inv.lines = slices.Clone(m.logEventLines[inv.first : inv.last+1])
+		items = append(items, inv)
+	}
+
+	m.invocations.ResetFilter()
+	m.invocations.SetItems(items)
+	m.invocations.Select(0)
+	m.invocations.Title = fmt.Sprintf("Invocations - %s", m.activeLogStream)
+	if m.logEventsBackwardToken != "" {
+		m.invocations.Title += " (loaded events only)"
+	}
+
+	m.activeView = viewInvocations
+}
+
+func (m model) viewInvocationsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.invocations.FilterState() == list.Filtering {
+		m.invocations, cmd = m.invocations.Update(msg)
+
+		return m, cmd
+	}
+
+	m.invocations, cmd = m.invocations.Update(msg)
+	selectedItem, hasSelectedItem := m.invocations.SelectedItem().(invocation)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			cmd = nil
+			if m.invocations.FilterValue() == "" {
+				m.activeView = viewLogEvent
+				cmd = m.resumeFollow()
+			}
+			m.invocations.ResetFilter()
+		case key.Matches(msg, m.keys.choose):
+			if !hasSelectedItem {
+				break
+			}
+
+			m.activeInvocation = selectedItem
+			m.invocationEvents.SetContent(strings.Join(selectedItem.lines, "\n"))
+			m.invocationEvents.GotoTop()
+			m.activeView = viewInvocationEvents
+		}
+	}
+
+	return m, cmd
+}
+
+func (m model) viewInvocationEventsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.invocationEvents, cmd = m.invocationEvents.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		m.activeView = viewInvocations
+		cmd = nil
+	}
+
+	return m, cmd
+}
+
+func (m model) invocationEventsView() string {
+	help := helpLine(keyHelp(m.keys.back, ""))
+	status := fmt.Sprintf("request %s • %s", m.activeInvocation.requestId, m.activeInvocation.status)
+	gap := m.winWidth - lipgloss.Width(help) - lipgloss.Width(status) - logEventStatusStyle.GetHorizontalFrameSize()
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.invocationEvents.View(),
+		logEventStatusStyle.Render(help+strings.Repeat(" ", max(gap, 1))+status),
+	)
+}
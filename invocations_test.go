@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInvocations(t *testing.T) {
+	rows := [][]string{
+		{"t0", "processing order"},
+		{"t1", "REPORT RequestId: a\tDuration: 10.00 ms\tBilled Duration: 10 ms\tMemory Size: 128 MB\tMax Memory Used: 60 MB"},
+		{"t2", "INIT_START Runtime Version: nodejs:20.v30"},
+		{"t3", "START RequestId: b Version: $LATEST"},
+		{"t4", "2024-09-01T00:00:00.000Z\tb\tERROR\tInvoke Error"},
+		{"t5", "END RequestId: b"},
+		{"t6", "REPORT RequestId: b\tDuration: 20.50 ms\tBilled Duration: 21 ms\tMemory Size: 128 MB\tMax Memory Used: 70 MB\tInit Duration: 150.25 ms"},
+		{"t7", "START RequestId: c Version: $LATEST"},
+		{"t8", "2024-09-01T00:00:03.000Z c Task timed out after 3.00 seconds"},
+		{"t9", "END RequestId: c"},
+		{"t10", "REPORT RequestId: c\tDuration: 3000.00 ms\tBilled Duration: 3000 ms\tMemory Size: 128 MB\tMax Memory Used: 80 MB\tStatus: timeout"},
+		{"t11", "START RequestId: d Version: $LATEST"},
+		{"t12", "still running"},
+	}
+
+	want := []invocation{
+		{requestId: "a", start: "t0", first: 0, last: 1, duration: "10.00 ms", billedDuration: "10 ms", memorySize: "128 MB", maxMemoryUsed: "60 MB", status: invocationOk},
+		{requestId: "b", start: "t3", first: 2, last: 6, duration: "20.50 ms", billedDuration: "21 ms", memorySize: "128 MB", maxMemoryUsed: "70 MB", initDuration: "150.25 ms", status: invocationError},
+		{requestId: "c", start: "t7", first: 7, last: 10, duration: "3000.00 ms", billedDuration: "3000 ms", memorySize: "128 MB", maxMemoryUsed: "80 MB", status: invocationTimeout},
+		{requestId: "d", start: "t11", first: 11, last: 12, status: invocationIncomplete},
+	}
+
+	if got := parseInvocations(rows); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseInvocationsMissingReport(t *testing.T) {
+	rows := [][]string{
+		{"t0", "START RequestId: a Version: $LATEST"},
+		{"t1", "working"},
+		{"t2", "REPORT RequestId: b\tDuration: 1.00 ms\tBilled Duration: 1 ms\tMemory Size: 128 MB\tMax Memory Used: 60 MB"},
+	}
+
+	got := parseInvocations(rows)
+	if len(got) != 2 || got[0].status != invocationIncomplete || got[0].last != 1 || got[1].requestId != "b" || got[1].first != 2 || got[1].status != invocationOk {
+		t.Fatalf("unexpected invocations: %+v", got)
+	}
+}
+
+func TestParseInvocationsStatusAfterMissingReport(t *testing.T) {
+	tests := []struct {
+		name   string
+		failed string
+		want   string
+	}{
+		{name: "error", failed: "[ERROR] payment declined", want: invocationError},
+		{name: "timeout", failed: "Task timed out after 3.00 seconds", want: invocationTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := [][]string{
+				{"t0", "START RequestId: a Version: $LATEST"},
+				{"t1", tt.failed},
+				{"t2", "START RequestId: b Version: $LATEST"},
+				{"t3", "END RequestId: b"},
+				{"t4", "REPORT RequestId: b\tDuration: 1.00 ms\tBilled Duration: 1 ms\tMemory Size: 128 MB\tMax Memory Used: 60 MB"},
+				{"t5", "START RequestId: c Version: $LATEST"},
+				{"t6", tt.failed},
+				{"t7", "REPORT RequestId: c\tDuration: 1.00 ms\tBilled Duration: 1 ms\tMemory Size: 128 MB\tMax Memory Used: 60 MB"},
+			}
+
+			got := parseInvocations(rows)
+			if len(got) != 3 || got[0].status != invocationIncomplete || got[1].status != invocationOk || got[2].status != tt.want {
+				t.Fatalf("unexpected invocations: %+v", got)
+			}
+		})
+	}
+}
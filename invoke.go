@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+type invokeReq struct {
+	region  string
+	name    string
+	payload string
+}
+
+type invokeMsg struct {
+	result invokeResult
+}
+
+var invokeErrorStyle = lipgloss.NewStyle()
+
+func (m model) viewInvokeUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.invokePayload.Blur()
+			m.activeView = viewLambda
+
+			return m, nil
+		case key.Matches(msg, m.keys.run):
+			payload := strings.TrimSpace(m.invokePayload.Value())
+			if payload == "" {
+				payload = "{}"
+			}
+
+			if !json.Valid([]byte(payload)) {
+				m.showError("payload is not valid JSON")
+
+				return m, nil
+			}
+
+			select {
+			case m.reqCh <- m.tag(invokeReq{region: m.invokeRegion, name: m.invokeTarget, payload: payload}):
+				m.invokePayload.Blur()
+				cmd = m.startLoading()
+			default:
+			}
+
+			return m, cmd
+		}
+	}
+
+	m.invokePayload, cmd = m.invokePayload.Update(msg)
+
+	return m, cmd
+}
+
+func (m model) viewInvokeResultUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.invokeOutput, cmd = m.invokeOutput.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		m.activeView = viewInvoke
+		cmd = m.invokePayload.Focus()
+	}
+
+	return m, cmd
+}
+
+func (m model) invokeView() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Invoke \"%s\" - %s", m.invokeTarget, m.sessionTitle())),
+		"",
+		m.invokePayload.View(),
+		"",
+		helpLine(keyHelp(m.keys.run, "invoke"), keyHelp(m.keys.back, "")),
+	)
+}
+
+func (m *model) onRcvInvokeMsg(msg invokeMsg) {
+	functionError := "-"
+	if msg.result.functionError != "" {
+		functionError = invokeErrorStyle.Render(msg.result.functionError)
+	}
+
+	summary := table.
+		New().
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(lambdaDetailTableStyleFunc).
+		Width(m.invokeOutput.Width).
+		Rows(
+			[]string{"Function", m.invokeTarget},
+			[]string{"Status Code", fmt.Sprintf("%d", msg.result.statusCode)},
+			[]string{"Executed Version", msg.result.executedVersion},
+			[]string{"Function Error", functionError},
+		)
+
+	content := lipgloss.JoinVertical(
+		0,
+		lambdaDetailTitleStyle.Render("Result"),
+		summary.Render(),
+		lambdaDetailTitleStyle.Render("Response Payload"),
+		lambdaDetailFieldValueStyle.Render(wrapString(msg.result.payload, m.invokeOutput.Width-8)),
+		"",
+		lambdaDetailTitleStyle.Render("Log Output"),
+		lambdaDetailFieldValueStyle.Render(wrapString(msg.result.log, m.invokeOutput.Width-8)),
+	)
+
+	m.invokeOutput.SetContent(content)
+	m.invokeOutput.GotoTop()
+	m.activeView = viewInvokeResult
+	m.loading = false
+}
+
+// newInvokePayload returns the text area used to edit invocation payloads.
+func newInvokePayload() textarea.Model {
+	t := textarea.New()
+	t.CharLimit = 0
+	t.MaxHeight = 0
+	t.Placeholder = "JSON payload"
+
+	return t
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A keyMap holds the key bindings of the actions of all views.
+type keyMap struct {
+	quit      key.Binding
+	forceQuit key.Binding
+	back      key.Binding
+	choose    key.Binding
+	// next and prev cycle through the environment variables, time ranges or
+	// form fields of a view.
+	next    key.Binding
+	prev    key.Binding
+	up      key.Binding
+	down    key.Binding
+	refresh key.Binding
+	run     key.Binding
+	apply   key.Binding
+	mouse   key.Binding
+
+	logStreams  key.Binding
+	invoke      key.Binding
+	insights    key.Binding
+	metrics     key.Binding
+	analytics   key.Binding
+	multiRegion key.Binding
+	refreshAll  key.Binding
+	profile     key.Binding
+
+	versions    key.Binding
+	triggers    key.Binding
+	concurrency key.Binding
+	editEnv     key.Binding
+	reveal      key.Binding
+	revealAll   key.Binding
+	// revealSecrets reveals the masked values in the environment editor.
+	revealSecrets key.Binding
+
+	searchGroup key.Binding
+
+	follow      key.Binding
+	formatAll   key.Binding
+	formatTop   key.Binding
+	search      key.Binding
+	export      key.Binding
+	invocations key.Binding
+	nextMatch   key.Binding
+	prevMatch   key.Binding
+	toggleRegex key.Binding
+
+	edit   key.Binding
+	add    key.Binding
+	remove key.Binding
+}
+
+// The scopes of key bindings. Bindings of the global scope are active in all
+// views, all others only in the views of their scope.
+const (
+	keyScopeGlobal      = "global"
+	keyScopeLambdas     = "lambdas"
+	keyScopeDetail      = "detail"
+	keyScopeVersions    = "versions"
+	keyScopeLogStreams  = "log streams"
+	keyScopeLogEvents   = "log events"
+	keyScopeLogSearch   = "log search"
+	keyScopeConcurrency = "concurrency"
+	keyScopeConfirm     = "confirmation"
+	keyScopeEditor      = "editor"
+	keyScopeRanges      = "time ranges"
+	keyScopeFilter      = "log group search"
+)
+
+// listScopes are the scopes of views showing a list, whose navigation keys
+// must not be bound to actions of the view.
+var listScopes = []string{keyScopeLambdas, keyScopeVersions, keyScopeLogStreams}
+
+// inputActions may be triggered while a text input is focused, where keys that
+// insert text are passed to the input instead. They need at least one key
+// that does not insert text.
+var inputActions = []string{"back", "choose", "next", "prev", "run", "revealSecrets", "toggleRegex"}
+
+// A keyAction is a configurable key binding.
+type keyAction struct {
+	// name identifies the binding in the config file.
+	name    string
+	binding *key.Binding
+	scopes  []string
+}
+
+// actions returns the configurable bindings of the key map.
+func (k *keyMap) actions() []keyAction {
+	return []keyAction{
+		{"quit", &k.quit, []string{keyScopeGlobal}},
+		{"forceQuit", &k.forceQuit, []string{keyScopeGlobal}},
+		{"back", &k.back, []string{keyScopeGlobal}},
+		{"choose", &k.choose, []string{keyScopeGlobal}},
+		{"next", &k.next, []string{keyScopeDetail, keyScopeRanges, keyScopeFilter}},
+		{"prev", &k.prev, []string{keyScopeDetail, keyScopeRanges, keyScopeFilter}},
+		{"up", &k.up, []string{keyScopeConcurrency}},
+		{"down", &k.down, []string{keyScopeConcurrency}},
+		{"refresh", &k.refresh, []string{keyScopeLogStreams, keyScopeRanges, keyScopeConcurrency}},
+		{"run", &k.run, []string{keyScopeEditor, keyScopeRanges}},
+		{"apply", &k.apply, []string{keyScopeConfirm}},
+		{"mouse", &k.mouse, []string{keyScopeGlobal}},
+		{"logStreams", &k.logStreams, []string{keyScopeLambdas, keyScopeVersions}},
+		{"invoke", &k.invoke, []string{keyScopeLambdas}},
+		{"insights", &k.insights, []string{keyScopeLambdas}},
+		{"metrics", &k.metrics, []string{keyScopeLambdas}},
+		{"analytics", &k.analytics, []string{keyScopeLambdas}},
+		{"multiRegion", &k.multiRegion, []string{keyScopeLambdas}},
+		{"refreshAll", &k.refreshAll, []string{keyScopeLambdas}},
+		{"profile", &k.profile, []string{keyScopeLambdas}},
+		{"versions", &k.versions, []string{keyScopeDetail}},
+		{"triggers", &k.triggers, []string{keyScopeDetail}},
+		{"concurrency", &k.concurrency, []string{keyScopeDetail}},
+		{"editEnv", &k.editEnv, []string{keyScopeDetail}},
+		{"reveal", &k.reveal, []string{keyScopeDetail}},
+		{"revealAll", &k.revealAll, []string{keyScopeDetail, keyScopeConfirm}},
+		{"revealSecrets", &k.revealSecrets, []string{keyScopeEditor}},
+		{"searchGroup", &k.searchGroup, []string{keyScopeLogStreams}},
+		{"follow", &k.follow, []string{keyScopeLogEvents}},
+		{"formatAll", &k.formatAll, []string{keyScopeLogEvents}},
+		{"formatTop", &k.formatTop, []string{keyScopeLogEvents}},
+		{"search", &k.search, []string{keyScopeLogEvents}},
+		{"export", &k.export, []string{keyScopeLogEvents}},
+		{"invocations", &k.invocations, []string{keyScopeLogEvents}},
+		{"nextMatch", &k.nextMatch, []string{keyScopeLogEvents}},
+		{"prevMatch", &k.prevMatch, []string{keyScopeLogEvents}},
+		{"toggleRegex", &k.toggleRegex, []string{keyScopeLogSearch}},
+		{"edit", &k.edit, []string{keyScopeConcurrency}},
+		{"add", &k.add, []string{keyScopeConcurrency}},
+		{"remove", &k.remove, []string{keyScopeConcurrency}},
+	}
+}
+
+func newBinding(help string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), help))
+}
+
+// listKeyMap returns the key map of lists. Unlike the default, pages are not
+// turned with letters like h and l, which are left to actions.
+func listKeyMap() list.KeyMap {
+	keys := list.DefaultKeyMap()
+	keys.PrevPage = newBinding("prev page", "pgup", "ctrl+u")
+	keys.NextPage = newBinding("next page", "pgdown", "ctrl+d")
+
+	return keys
+}
+
+// logEventsKeyMap returns the key map of the log events viewport. Unlike the
+// default, pages are not turned with f, which is left to following.
+func logEventsKeyMap() viewport.KeyMap {
+	keys := viewport.DefaultKeyMap()
+	keys.PageDown = key.NewBinding(key.WithKeys("pgdown", " "), key.WithHelp("pgdn", "page down"))
+
+	return keys
+}
+
+// insertsText reports whether a key inserts text, in which case it is passed
+// to a focused text input rather than triggering an action.
+func insertsText(msg tea.KeyMsg) bool {
+	return (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) && !msg.Alt
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		quit:      newBinding("quit", "q"),
+		forceQuit: newBinding("quit", "ctrl+c"),
+		back:      newBinding("back", "esc"),
+		choose:    newBinding("select", "enter"),
+		next:      newBinding("next", "tab"),
+		prev:      newBinding("prev", "shift+tab"),
+		up:        newBinding("up", "up", "k"),
+		down:      newBinding("down", "down", "j"),
+		refresh:   newBinding("refresh", "r"),
+		run:       newBinding("run", "ctrl+s"),
+		apply:     newBinding("apply", "y"),
+		mouse:     newBinding("toggle mouse", "ctrl+o"),
+
+		logStreams:  newBinding("log streams", "l"),
+		invoke:      newBinding("invoke", "i"),
+		insights:    newBinding("insights", "I"),
+		metrics:     newBinding("metrics", "M"),
+		analytics:   newBinding("analytics", "A"),
+		multiRegion: newBinding("multi-region", "m"),
+		refreshAll:  newBinding("refresh all", "R"),
+		profile:     newBinding("profile/region", "p"),
+
+		versions:    newBinding("versions/aliases", "v"),
+		triggers:    newBinding("triggers", "t"),
+		concurrency: newBinding("concurrency", "C"),
+		editEnv:     newBinding("edit environment", "E"),
+		reveal:      newBinding("reveal variable", "x"),
+		revealAll:   newBinding("reveal all", "X"),
+		// Keys of the editor must not insert text.
+		revealSecrets: newBinding("reveal secrets", "ctrl+x"),
+
+		searchGroup: newBinding("search all streams", "s"),
+
+		follow:      newBinding("follow", "f"),
+		formatAll:   newBinding("format all", "J"),
+		formatTop:   newBinding("format top event", "e"),
+		search:      newBinding("search", "/"),
+		export:      newBinding("export", "x"),
+		invocations: newBinding("invocations", "i"),
+		nextMatch:   newBinding("next match", "n"),
+		prevMatch:   newBinding("prev match", "N"),
+		toggleRegex: newBinding("regex", "ctrl+r"),
+
+		edit:   newBinding("edit", "e"),
+		add:    newBinding("add provisioned", "a"),
+		remove: newBinding("remove", "d"),
+	}
+}
+
+// A userConfig is the content of the config file, e.g.
+//
+//	{"keys": {"back": ["esc", "h"], "logStreams": ["l", "right"]}}
+type userConfig struct {
+	// Keys maps action names to the keys they are bound to, replacing the
+	// default keys.
+	Keys map[string][]string `json:"keys"`
+}
+
+// defaultConfigPath returns the path of the config file in the user's config
+// directory, e.g. $XDG_CONFIG_HOME/lambda-tui/config.json.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "lambda-tui", "config.json")
+}
+
+// loadKeyMap returns the default key map with the bindings of the config file
+// at path applied. A missing config file leaves the defaults unchanged.
+func loadKeyMap(path string) (keyMap, error) {
+	keys := defaultKeyMap()
+	if path == "" {
+		return keys, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return keys, nil
+	} else if err != nil {
+		return keyMap{}, err
+	}
+
+	var cfg userConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return keyMap{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if err := keys.bind(cfg.Keys); err != nil {
+		return keyMap{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return keys, nil
+}
+
+// bind rebinds the actions of the key map and checks the bindings for
+// conflicts.
+func (k *keyMap) bind(bindings map[string][]string) error {
+	actions := k.actions()
+
+	for name, keys := range bindings {
+		i := slices.IndexFunc(actions, func(a keyAction) bool { return a.name == name })
+		if i < 0 {
+			return fmt.Errorf("unknown key binding %q", name)
+		}
+
+		if len(keys) == 0 {
+			return fmt.Errorf("no keys given for %q", name)
+		}
+
+		*actions[i].binding = newBinding(actions[i].binding.Help().Desc, keys...)
+	}
+
+	return k.validate()
+}
+
+// validate reports keys that are bound to more than one action of a view or
+// to an action and the navigation of a list or the log events, and actions of
+// text inputs that can only be triggered by keys inserting text.
+func (k *keyMap) validate() error {
+	actions := k.actions()
+
+	for _, a := range actions {
+		if slices.Contains(inputActions, a.name) && !slices.ContainsFunc(a.binding.Keys(), func(key string) bool {
+			return utf8.RuneCountInString(key) > 1
+		}) {
+			return fmt.Errorf("%q is also used in text inputs and needs a key that does not insert text, like esc or ctrl+x", a.name)
+		}
+	}
+
+	listKeys, logEventKeys := listKeyMap(), logEventsKeyMap()
+	navigation := []struct {
+		view     string
+		scopes   []string
+		bindings []key.Binding
+	}{
+		{"lists", listScopes, []key.Binding{
+			listKeys.CursorUp,
+			listKeys.CursorDown,
+			listKeys.PrevPage,
+			listKeys.NextPage,
+			listKeys.GoToStart,
+			listKeys.GoToEnd,
+			listKeys.Filter,
+			listKeys.ShowFullHelp,
+		}},
+		{"log events", []string{keyScopeLogEvents}, []key.Binding{
+			logEventKeys.Up,
+			logEventKeys.Down,
+			logEventKeys.PageUp,
+			logEventKeys.PageDown,
+			logEventKeys.HalfPageUp,
+			logEventKeys.HalfPageDown,
+		}},
+	}
+
+	for _, n := range navigation {
+		for _, a := range actions {
+			if !scopesOverlap(a.scopes, n.scopes) {
+				continue
+			}
+
+			for _, b := range n.bindings {
+				for _, key := range a.binding.Keys() {
+					if slices.Contains(b.Keys(), key) {
+						return fmt.Errorf("key %q is bound to both %q and %q of %s", key, a.name, b.Help().Desc, n.view)
+					}
+				}
+			}
+		}
+	}
+
+	for i, a := range actions {
+		for _, b := range actions[i+1:] {
+			if !scopesOverlap(a.scopes, b.scopes) {
+				continue
+			}
+
+			for _, key := range a.binding.Keys() {
+				if slices.Contains(b.binding.Keys(), key) {
+					return fmt.Errorf("key %q is bound to both %q and %q", key, a.name, b.name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// scopesOverlap reports whether bindings of the given scopes may be active in
+// the same view.
+func scopesOverlap(a []string, b []string) bool {
+	if slices.Contains(a, keyScopeGlobal) || slices.Contains(b, keyScopeGlobal) {
+		return true
+	}
+
+	return slices.ContainsFunc(a, func(scope string) bool { return slices.Contains(b, scope) })
+}
+
+// describe returns the binding with another description for help.
+func describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+
+	return b
+}
+
+// keyHelp describes a binding for a help line, with desc replacing the
+// description of the binding unless empty.
+func keyHelp(b key.Binding, desc string) string {
+	if desc == "" {
+		desc = b.Help().Desc
+	}
+
+	return b.Help().Key + " " + desc
+}
+
+// helpLine renders help for the given descriptions of bindings.
+func helpLine(items ...string) string {
+	return helpStyle.Render(strings.Join(items, " • "))
+}
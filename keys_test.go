@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestDefaultKeyMap(t *testing.T) {
+	keys := defaultKeyMap()
+	if err := keys.validate(); err != nil {
+		t.Fatalf("default key map has conflicts: %v", err)
+	}
+
+	for _, action := range keys.actions() {
+		if len(action.binding.Keys()) == 0 || action.binding.Help().Desc == "" {
+			t.Errorf("action %q has no keys or help", action.name)
+		}
+	}
+}
+
+func TestLoadKeyMap(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	keys, err := loadKeyMap(filepath.Join(dir, "missing.json"))
+	if err != nil || !key.Matches(keyMsg("l"), keys.logStreams) {
+		t.Fatalf("expected the default key map for a missing config, got %v", err)
+	}
+
+	keys, err = loadKeyMap(write(`{"keys": {"back": ["esc", "h"], "logStreams": ["L"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !key.Matches(keyMsg("h"), keys.back) || !key.Matches(keyMsg("L"), keys.logStreams) || key.Matches(keyMsg("l"), keys.logStreams) {
+		t.Fatalf("bindings were not applied: %v %v", keys.back.Keys(), keys.logStreams.Keys())
+	}
+
+	if help := keys.logStreams.Help(); help.Key != "L" || help.Desc != "log streams" {
+		t.Fatalf("got help %+v", help)
+	}
+
+	for content, want := range map[string]string{
+		`{"keys": {"logStreams": ["i"]}}`: `key "i" is bound to both "logStreams" and "invoke"`,
+		`{"keys": {"follow": ["esc"]}}`:   `key "esc" is bound to both "back" and "follow"`,
+		`{"keys": {"unknown": ["u"]}}`:    `unknown key binding "unknown"`,
+		`{"keys": {"back": ["h"]}}`:       `"back" is also used in text inputs`,
+		`{"keys": {"logStreams": ["g"]}}`: `key "g" is bound to both "logStreams" and "go to start" of lists`,
+		`{"keys": {"quit": ["/"]}}`:       `key "/" is bound to both "quit" and "filter" of lists`,
+		`{"keys": {"follow": ["j"]}}`:     `key "j" is bound to both "follow" and "down" of log events`,
+		`{"keys": {"search": ["b"]}}`:     `key "b" is bound to both "search" and "page up" of log events`,
+		`{"keys": {"back": []}}`:          `no keys given for "back"`,
+		`{"keys": [}`:                     "parsing",
+	} {
+		if _, err := loadKeyMap(write(content)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("loading %s: got error %v, want %q", content, err, want)
+		}
+	}
+
+	// Actions of different views may share keys.
+	if _, err := loadKeyMap(write(`{"keys": {"follow": ["i"], "invocations": ["I"]}}`)); err != nil {
+		t.Fatalf("unexpected conflict: %v", err)
+	}
+}
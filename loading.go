@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultRequestTimeout bounds the duration of a single request unless
+// configured otherwise.
+const defaultRequestTimeout = 30 * time.Second
+
+// A requestCanceler holds the cancel function of the request currently being
+// handled, so that the model can abort it.
+type requestCanceler struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+func (c *requestCanceler) set(cancel context.CancelFunc) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cancel = cancel
+}
+
+// cancelCurrent aborts the request currently being handled, if any.
+func (c *requestCanceler) cancelCurrent() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+// A taggedReq is a request the model waits for while loading. Its id tells
+// its response apart from those of requests the user has cancelled.
+type taggedReq struct {
+	id  int
+	req interface{}
+}
+
+// A taggedMsg is the response to a tagged request.
+type taggedMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// tag assigns the next id to a request the model is about to wait for.
+func (m *model) tag(req interface{}) taggedReq {
+	m.requestId++
+
+	return taggedReq{id: m.requestId, req: req}
+}
+
+// untagRequest returns the id of a tagged request, zero for other requests,
+// and the request itself.
+func untagRequest(req interface{}) (int, interface{}) {
+	if tagged, ok := req.(taggedReq); ok {
+		return tagged.id, tagged.req
+	}
+
+	return 0, req
+}
+
+// tagResponse tags the response to the request of the given id.
+func tagResponse(id int, msg tea.Msg) tea.Msg {
+	if id == 0 || msg == nil {
+		return msg
+	}
+
+	return taggedMsg{id: id, msg: msg}
+}
+
+// A viewState holds the fields that are updated when a request is sent, so
+// they can be restored if the request is cancelled.
+type viewState struct {
+	// requestId is the id of the request being waited for.
+	requestId             int
+	activeView            string
+	activeLambda          string
+	activeLambdaRegion    string
+	activeLambdaQualifier string
+	activeLogGroup        string
+	activeLogRegion       string
+	activeLogStream       string
+	logStreamQualifier    string
+	logStreamVersions     []string
+}
+
+var loadingHintStyle = lipgloss.NewStyle()
+
+// startLoading shows the spinner until the response to a request arrives. It
+// must be called before the model is updated for the request.
+func (m *model) startLoading() tea.Cmd {
+	m.loading = true
+	m.loadingSince = time.Now()
+	m.beforeLoading = viewState{
+		requestId:             m.requestId,
+		activeView:            m.activeView,
+		activeLambda:          m.activeLambda,
+		activeLambdaRegion:    m.activeLambdaRegion,
+		activeLambdaQualifier: m.activeLambdaQualifier,
+		activeLogGroup:        m.activeLogGroup,
+		activeLogRegion:       m.activeLogRegion,
+		activeLogStream:       m.activeLogStream,
+		logStreamQualifier:    m.logStreamQualifier,
+		logStreamVersions:     m.logStreamVersions,
+	}
+
+	return m.spinner.Tick
+}
+
+// cancelLoading aborts the pending request and returns to the view it was
+// sent from.
+func (m *model) cancelLoading() tea.Cmd {
+	m.canceler.cancelCurrent()
+	m.loading = false
+	m.exportProgress = nil
+	m.activeView = m.beforeLoading.activeView
+	m.activeLambda = m.beforeLoading.activeLambda
+	m.activeLambdaRegion = m.beforeLoading.activeLambdaRegion
+	m.activeLambdaQualifier = m.beforeLoading.activeLambdaQualifier
+	m.activeLogGroup = m.beforeLoading.activeLogGroup
+	m.activeLogRegion = m.beforeLoading.activeLogRegion
+	m.activeLogStream = m.beforeLoading.activeLogStream
+	m.logStreamQualifier = m.beforeLoading.logStreamQualifier
+	m.logStreamVersions = m.beforeLoading.logStreamVersions
+
+	switch m.activeView {
+	case viewInvoke:
+		return m.invokePayload.Focus()
+	case viewInsights:
+		return m.insightsQuery.Focus()
+	case viewEnvEdit:
+		return m.envEditor.Focus()
+	}
+
+	return nil
+}
+
+func (m model) loadingView() string {
+	elapsed := time.Since(m.loadingSince).Truncate(100 * time.Millisecond)
+
+	status := fmt.Sprintf("%s Loading... %s", m.spinner.View(), elapsed)
+	if m.exportProgress != nil {
+		status = fmt.Sprintf("%s Exporting... %d events • %s", m.spinner.View(), m.exportProgress.Load(), elapsed)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		status,
+		"",
+		loadingHintStyle.Render(fmt.Sprintf("Press %s to cancel", m.keys.back.Help().Key)),
+	)
+}
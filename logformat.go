@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A logFormat determines how JSON log messages are rendered.
+type logFormat int
+
+const (
+	logFormatRaw logFormat = iota
+	logFormatCompact
+	logFormatExpanded
+)
+
+func (f logFormat) next() logFormat {
+	return (f + 1) % 3
+}
+
+func (f logFormat) String() string {
+	switch f {
+	case logFormatCompact:
+		return "compact"
+	case logFormatExpanded:
+		return "expanded"
+	default:
+		return "raw"
+	}
+}
+
+var (
+	jsonKeyStyle     = lipgloss.NewStyle().Bold(true)
+	jsonStringStyle  = lipgloss.NewStyle()
+	jsonNumberStyle  = lipgloss.NewStyle()
+	jsonLiteralStyle = lipgloss.NewStyle()
+
+	logLevelStyles = map[string]lipgloss.Style{
+		"TRACE": lipgloss.NewStyle().Bold(true),
+		"DEBUG": lipgloss.NewStyle().Bold(true),
+		"INFO":  lipgloss.NewStyle().Bold(true),
+		"WARN":  lipgloss.NewStyle().Bold(true),
+		"ERROR": lipgloss.NewStyle().Bold(true),
+		"FATAL": lipgloss.NewStyle().Bold(true).Underline(true),
+	}
+)
+
+// splitJSONMessage splits a log message into a text prefix and a JSON body.
+// Besides plain JSON messages, this recognizes the tab separated text format
+// of the Lambda runtimes, where the JSON body is the last field. ok is false
+// if the message holds no JSON.
+func splitJSONMessage(message string) (prefix string, body string, ok bool) {
+	trimmed := strings.TrimSpace(message)
+	if isJSONBody(trimmed) {
+		return "", trimmed, true
+	}
+
+	i := strings.LastIndex(message, "\t")
+	if i < 0 {
+		return "", "", false
+	}
+
+	if last := strings.TrimSpace(message[i+1:]); isJSONBody(last) {
+		return message[:i+1], last, true
+	}
+
+	return "", "", false
+}
+
+func isJSONBody(s string) bool {
+	return (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) && json.Valid([]byte(s))
+}
+
+// formatLogMessage renders a log message in the given format. Messages
+// without JSON are returned as is. In compact format, messages of the Lambda
+// JSON log format are summarized by their level and message.
+func formatLogMessage(message string, format logFormat, colorize bool) string {
+	if format == logFormatRaw {
+		return message
+	}
+
+	prefix, body, ok := splitJSONMessage(message)
+	if !ok {
+		return message
+	}
+
+	var b bytes.Buffer
+	if format == logFormatExpanded {
+		_ = json.Indent(&b, []byte(body), "", "  ")
+	} else if summary, ok := summarizeLambdaLog(body, colorize); ok {
+		return prefix + summary
+	} else {
+		_ = json.Compact(&b, []byte(body))
+	}
+
+	if colorize {
+		return prefix + colorizeJSON(b.String())
+	}
+
+	return prefix + b.String()
+}
+
+// summarizeLambdaLog renders a message of the Lambda JSON log format as its
+// level and message followed by the remaining fields.
+func summarizeLambdaLog(body string, colorize bool) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return "", false
+	}
+
+	var level, message string
+	if json.Unmarshal(fields["level"], &level) != nil || fields["message"] == nil {
+		return "", false
+	}
+
+	// The message field may hold structured data itself.
+	if json.Unmarshal(fields["message"], &message) != nil {
+		var b bytes.Buffer
+		_ = json.Compact(&b, fields["message"])
+		message = b.String()
+	}
+
+	delete(fields, "level")
+	delete(fields, "message")
+	delete(fields, "timestamp")
+
+	level = strings.ToUpper(level)
+	if style, ok := logLevelStyles[level]; ok && colorize {
+		level = style.Render(level)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var rest bytes.Buffer
+	for i, k := range keys {
+		if i > 0 {
+			rest.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(k)
+		rest.Write(key)
+		rest.WriteByte(':')
+		_ = json.Compact(&rest, fields[k])
+	}
+
+	summary := level + " " + message
+	if rest.Len() == 0 {
+		return summary, true
+	}
+
+	other := "{" + rest.String() + "}"
+	if colorize {
+		other = colorizeJSON(other)
+	}
+
+	return summary + " " + other, true
+}
+
+// colorizeJSON adds syntax highlighting to valid, formatted JSON.
+func colorizeJSON(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(s))
+
+			style := jsonStringStyle
+			if strings.HasPrefix(strings.TrimLeft(s[end:], " \t\n"), ":") {
+				style = jsonKeyStyle
+			}
+
+			b.WriteString(style.Render(s[i:end]))
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + strings.IndexFunc(s[i:]+" ", func(r rune) bool {
+				return !strings.ContainsRune("+-.0123456789eE", r)
+			})
+			b.WriteString(jsonNumberStyle.Render(s[i:end]))
+			i = end
+		case c >= 'a' && c <= 'z':
+			end := i + strings.IndexFunc(s[i:]+" ", func(r rune) bool {
+				return r < 'a' || r > 'z'
+			})
+			b.WriteString(jsonLiteralStyle.Render(s[i:end]))
+			i = end
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
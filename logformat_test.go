@@ -0,0 +1,77 @@
+package main
+
+import "testing"
+
+func TestFormatLogMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		format  logFormat
+		want    string
+	}{
+		{
+			name:    "raw",
+			message: `{"a": 1}`,
+			format:  logFormatRaw,
+			want:    `{"a": 1}`,
+		},
+		{
+			name:    "plain text",
+			message: "order created",
+			format:  logFormatExpanded,
+			want:    "order created",
+		},
+		{
+			name:    "compact",
+			message: `{ "a": 1, "b": [true, null] }`,
+			format:  logFormatCompact,
+			want:    `{"a":1,"b":[true,null]}`,
+		},
+		{
+			name:    "expanded",
+			message: `{"a":1,"b":{"c":"d"}}`,
+			format:  logFormatExpanded,
+			want:    "{\n  \"a\": 1,\n  \"b\": {\n    \"c\": \"d\"\n  }\n}",
+		},
+		{
+			name:    "runtime text format",
+			message: "2024-09-01T12:00:00.000Z\tabc-123\tINFO\t{\"orderId\": 42}",
+			format:  logFormatCompact,
+			want:    "2024-09-01T12:00:00.000Z\tabc-123\tINFO\t{\"orderId\":42}",
+		},
+		{
+			name:    "lambda json format",
+			message: `{"timestamp":"2024-09-01T12:00:00Z","level":"info","message":"order created","requestId":"abc-123"}`,
+			format:  logFormatCompact,
+			want:    `INFO order created {"requestId":"abc-123"}`,
+		},
+		{
+			name:    "lambda json format with structured message",
+			message: `{"level":"ERROR","message":{"code": 500}}`,
+			format:  logFormatCompact,
+			want:    `ERROR {"code":500}`,
+		},
+		{
+			name:    "invalid json",
+			message: `{"a": `,
+			format:  logFormatCompact,
+			want:    `{"a": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatLogMessage(tt.message, tt.format, false); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorizeJSONKeepsText(t *testing.T) {
+	// Without a color profile, styles render as plain text.
+	in := "{\n  \"a\": -1.5e3,\n  \"b\": [true, false, null, \"x\\\"y\"]\n}"
+	if got := colorizeJSON(in); got != in {
+		t.Errorf("got %q, want %q", got, in)
+	}
+}
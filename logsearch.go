@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	logSearchMatchStyle        = lipgloss.NewStyle()
+	logSearchCurrentMatchStyle = lipgloss.NewStyle().Bold(true)
+	logSearchErrStyle          = lipgloss.NewStyle()
+)
+
+// compileLogSearch builds the expression searched for in log messages. Plain
+// searches match the query literally and ignore case unless the query
+// contains an upper case letter.
+func compileLogSearch(query string, isRegex bool) (*regexp.Regexp, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	expr := query
+	if !isRegex {
+		expr = regexp.QuoteMeta(query)
+		if !strings.ContainsFunc(query, unicode.IsUpper) {
+			expr = "(?i)" + expr
+		}
+	}
+
+	return regexp.Compile(expr)
+}
+
+// matchIndexes returns the non-empty matches of re in s.
+func matchIndexes(re *regexp.Regexp, s string) [][]int {
+	matches := re.FindAllStringIndex(s, -1)
+
+	return slices.DeleteFunc(matches, func(match []int) bool {
+		return match[0] == match[1]
+	})
+}
+
+func highlightMatches(s string, re *regexp.Regexp, style lipgloss.Style) string {
+	matches := matchIndexes(re, s)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		b.WriteString(s[last:match[0]])
+		b.WriteString(style.Render(s[match[0]:match[1]]))
+		last = match[1]
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+func newLogSearchInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "/"
+	t.Placeholder = "search"
+
+	return t
+}
+
+func (m model) viewLogSearchUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && !insertsText(msg) {
+		switch {
+		case key.Matches(msg, m.keys.choose):
+			m.logSearching = false
+			m.logSearchInput.Blur()
+
+			return m, nil
+		case key.Matches(msg, m.keys.back):
+			m.logSearching = false
+			m.logSearchInput.Blur()
+			m.logSearchInput.SetValue("")
+			m.updateLogSearch()
+
+			return m, nil
+		case key.Matches(msg, m.keys.toggleRegex):
+			m.logSearchRegex = !m.logSearchRegex
+			m.updateLogSearch()
+
+			return m, nil
+		}
+	}
+
+	previous := m.logSearchInput.Value()
+	m.logSearchInput, cmd = m.logSearchInput.Update(msg)
+	if m.logSearchInput.Value() != previous {
+		m.updateLogSearch()
+	}
+
+	return m, cmd
+}
+
+// updateLogSearch recomputes the matches of the search query and jumps to
+// the first match at or below the current scroll position.
+func (m *model) updateLogSearch() {
+	previousMatches := m.logSearchMatches
+
+	re, err := compileLogSearch(m.logSearchInput.Value(), m.logSearchRegex)
+	m.logSearchErr = err
+	m.logSearchExpr = re
+	m.logSearchMatches = nil
+	m.logSearchCurrent = 0
+
+	if re != nil {
+		for i := range m.logEventRows {
+			if m.logEventMatches(i) {
+				m.logSearchMatches = append(m.logSearchMatches, i)
+			}
+		}
+
+		for i, row := range m.logSearchMatches {
+			if m.logEventOffsets[row] >= m.logEvents.YOffset {
+				m.logSearchCurrent = i
+
+				break
+			}
+		}
+	}
+
+	m.renderLogEventRows(append(previousMatches, m.logSearchMatches...)...)
+	m.scrollToLogSearchMatch()
+}
+
+// searchNewLogEvents adds matches within the rows from index start on.
+func (m *model) searchNewLogEvents(start int) {
+	if m.logSearchExpr == nil {
+		return
+	}
+
+	for i := start; i < len(m.logEventRows); i++ {
+		if m.logEventMatches(i) {
+			m.logSearchMatches = append(m.logSearchMatches, i)
+		}
+	}
+}
+
+// searchPrependedLogEvents adds matches within the first n rows, which have
+// been inserted before the previously searched rows.
+func (m *model) searchPrependedLogEvents(n int) {
+	if m.logSearchExpr == nil {
+		return
+	}
+
+	var matches []int
+	for i := 0; i < n; i++ {
+		if m.logEventMatches(i) {
+			matches = append(matches, i)
+		}
+	}
+
+	for _, row := range m.logSearchMatches {
+		matches = append(matches, row+n)
+	}
+
+	if len(m.logSearchMatches) > 0 {
+		m.logSearchCurrent += len(matches) - len(m.logSearchMatches)
+	}
+	m.logSearchMatches = matches
+}
+
+// refreshLogSearchMatches recomputes the matches after the format of log
+// events changed. The current match stays on the same or the closest preceding
+// row.
+func (m *model) refreshLogSearchMatches() {
+	if m.logSearchExpr == nil {
+		return
+	}
+
+	current := -1
+	if len(m.logSearchMatches) > 0 {
+		current = m.logSearchMatches[m.logSearchCurrent]
+	}
+
+	m.logSearchMatches = nil
+	m.logSearchCurrent = 0
+	for i := range m.logEventRows {
+		if !m.logEventMatches(i) {
+			continue
+		}
+
+		if i <= current {
+			m.logSearchCurrent = len(m.logSearchMatches)
+		}
+		m.logSearchMatches = append(m.logSearchMatches, i)
+	}
+}
+
+// logEventMatches reports whether the search matches the log event as it is
+// rendered.
+func (m model) logEventMatches(row int) bool {
+	return len(matchIndexes(m.logSearchExpr, m.logEventMessage(row))) > 0
+}
+
+// logEventMessage returns the message of a log event formatted without
+// colours, which is the text searches match.
+func (m model) logEventMessage(row int) string {
+	return formatLogMessage(m.logEventRows[row][1], m.logEventFormat(row), false)
+}
+
+// moveLogSearchMatch moves the current match by delta, wrapping around at
+// either end.
+func (m *model) moveLogSearchMatch(delta int) {
+	if len(m.logSearchMatches) == 0 {
+		return
+	}
+
+	previous := m.logSearchMatches[m.logSearchCurrent]
+	m.logSearchCurrent = (m.logSearchCurrent + delta + len(m.logSearchMatches)) % len(m.logSearchMatches)
+	m.renderLogEventRows(previous, m.logSearchMatches[m.logSearchCurrent])
+	m.scrollToLogSearchMatch()
+}
+
+func (m *model) scrollToLogSearchMatch() {
+	if len(m.logSearchMatches) == 0 {
+		return
+	}
+
+	m.logEvents.SetYOffset(m.logEventOffsets[m.logSearchMatches[m.logSearchCurrent]])
+}
+
+// isCurrentLogSearchMatch reports whether row holds the current match.
+func (m model) isCurrentLogSearchMatch(row int) bool {
+	return len(m.logSearchMatches) > 0 && m.logSearchMatches[m.logSearchCurrent] == row
+}
+
+func (m model) logSearchStatusView() string {
+	switch {
+	case m.logSearchErr != nil:
+		return logSearchErrStyle.Render("invalid regex")
+	case m.logSearchExpr == nil:
+		return ""
+	case len(m.logSearchMatches) == 0:
+		return logSearchErrStyle.Render("no matches")
+	default:
+		return fmt.Sprintf("match %d/%d", m.logSearchCurrent+1, len(m.logSearchMatches))
+	}
+}
+
+func (m model) logSearchPromptView() string {
+	prompt := m.logSearchInput.View()
+	if m.logSearchRegex {
+		prompt += helpStyle.Render("[regex]")
+	}
+
+	return prompt + helpLine(keyHelp(m.keys.choose, "confirm"), keyHelp(m.keys.toggleRegex, "toggle regex"), keyHelp(m.keys.back, "clear"))
+}
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
-	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -17,7 +21,8 @@ type lambdaDetailMsg struct {
 }
 
 type logStreamMsg struct {
-	items []logStream
+	items     []logStream
+	nextToken string
 }
 
 // A log event message is being sent to the model containing
@@ -27,7 +32,14 @@ type logStreamMsg struct {
 //		{event timestamp, message},
 //	}
 type logEventMsg struct {
-	events [][]string
+	events        [][]string
+	forwardToken  string
+	backwardToken string
+}
+
+type lambdaListMsg struct {
+	items       []list.Item
+	multiRegion bool
 }
 
 type errMsg struct {
@@ -37,6 +49,8 @@ type errMsg struct {
 type lambdaItem struct {
 	name     string
 	logGroup string
+	// region is only set when listing functions of multiple regions.
+	region string
 }
 
 func (l lambdaItem) Title() string {
@@ -44,6 +58,10 @@ func (l lambdaItem) Title() string {
 }
 
 func (l lambdaItem) Description() string {
+	if l.region != "" {
+		return fmt.Sprintf("%s - %s", l.region, l.logGroup)
+	}
+
 	return l.logGroup
 }
 
@@ -85,25 +103,112 @@ func main() {
 }
 
 func run() error {
-	cfg, err := config.LoadDefaultConfig(context.Background())
+	multiRegion := flag.Bool("multi-region", false, "list the functions of all regions given by -regions")
+	regionList := flag.String("regions", "", "comma separated list of regions to list functions from in multi-region mode")
+	timeout := flag.Duration("timeout", defaultRequestTimeout, "timeout of a single request, or of each AWS call of a command")
+	maskEnv := flag.String("mask-env", string(maskSecrets), "environment variable values to mask: all, secrets or none")
+	useCache := flag.Bool("cache", true, "cache function lists and details on disk")
+	cacheTTL := flag.Duration("cache-ttl", defaultCacheTTL, "age after which cached function lists and details are refreshed")
+	configPath := flag.String("config", defaultConfigPath(), "config file defining key bindings")
+	mouse := flag.Bool("mouse", true, "capture the mouse; text can only be selected with the mouse while it is not captured")
+	themeName := flag.String("theme", "auto", "colour theme: auto, dark, light, high-contrast or the path of a theme file")
+	secretPatterns := flag.String("secret-patterns", strings.Join(defaultSecretPatterns, ","), "comma separated glob patterns of environment variable names masked as secrets")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: lambdatui [flags] [command]\n\nWithout a command the TUI is started.\n\n%s\nFlags:\n", commandUsage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	masker, err := newEnvMasker(*maskEnv, parseList(*secretPatterns))
+	if err != nil {
+		return err
+	}
+
+	regions := parseList(*regionList)
+	if *multiRegion && len(regions) == 0 {
+		return fmt.Errorf("-multi-region requires a list of regions via -regions")
+	}
+
+	// Commands make any number of requests, so the timeout applies to each
+	// of them rather than to the whole command.
+	var apiOptions []func(*middleware.Stack) error
+	if flag.NArg() > 0 {
+		apiOptions = append(apiOptions, withCallTimeout(*timeout))
+	}
+
+	sess, err := newSession(context.Background(), "", "", apiOptions...)
 	if err != nil {
 		return err
 	}
 
-	credentials, err := cfg.Credentials.Retrieve(context.Background())
+	var listRegions []string
+	if *multiRegion {
+		listRegions = regions
+	}
+
+	if flag.NArg() > 0 {
+		c := cli{sess: sess, listRegions: listRegions, masker: masker, out: os.Stdout}
+
+		return c.run(context.Background(), flag.Args())
+	}
 
-	lambdaClient := lambda.NewFromConfig(cfg)
-	cloudwatchClient := cloudwatchlogs.NewFromConfig(cfg)
-	items, err := getLambdaFunctions(context.Background(), lambdaClient)
+	keys, err := loadKeyMap(*configPath)
 	if err != nil {
 		return err
 	}
 
+	colors, err := loadTheme(*themeName)
+	if err != nil {
+		return err
+	}
+
+	if *useCache {
+		// Secrets are not persisted, even if they are shown.
+		secrets := masker
+		if secrets.mode == maskNone {
+			secrets.mode = maskSecrets
+		}
+
+		sess.cache = newLambdaCache(defaultCacheDir(), *cacheTTL, secrets)
+	}
+
+	// Cached functions are shown immediately and refreshed once the program
+	// has started.
+	items, cachedAt, ok := sess.cache.functions(sess.cacheAccount(), sess.region, listRegions)
+	if !ok {
+		items, err = listLambdaFunctions(context.Background(), sess, listRegions)
+		if err != nil {
+			return err
+		}
+
+		sess.cache.storeFunctions(sess.cacheAccount(), sess.region, listRegions, items)
+	}
+
 	reqCh := make(chan interface{})
-	model := newModel(reqCh, credentials.AccountID, items)
+	canceler := &requestCanceler{}
+	model := newModel(reqCh, canceler, sess, regions, *multiRegion, items)
+	model.envMasker = masker
+	model.setKeyMap(keys)
+	model.setTheme(colors)
+	model.cache = sess.cache
+	model.lambdasCachedAt = cachedAt
+	model.lambdas.Title = model.lambdasTitle()
+
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if *mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	} else {
+		model.mouseDisabled = true
+	}
+
+	p := tea.NewProgram(model, options...)
+	go handleRequests(p, sess, reqCh, canceler, *timeout)
 
-	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	go handleRequests(p, cloudwatchClient, lambdaClient, reqCh)
+	if ok && sess.cache.stale(cachedAt) {
+		go func() {
+			reqCh <- lambdaListRefreshReq{regions: listRegions}
+		}()
+	}
 
 	if _, err := p.Run(); err != nil {
 		return err
@@ -112,40 +217,298 @@ func run() error {
 	return nil
 }
 
-func handleRequests(p *tea.Program, cwClient *cloudwatchlogs.Client, lambdaClient *lambda.Client, reqCh <-chan interface{}) {
-	for {
-		msg := <-reqCh
-		switch msg := msg.(type) {
-		case logStreamReq:
-			streams, err := getLogStreams(context.Background(), cwClient, msg.logGroup)
-			if err != nil {
-				log.Printf("[Error] %v", err)
-				p.Send(errMsg{err})
-				continue
-			}
+// parseList splits a comma separated list, ignoring empty elements.
+func parseList(list string) []string {
+	elements := make([]string, 0)
+	for _, element := range strings.Split(list, ",") {
+		element = strings.TrimSpace(element)
+		if element != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return elements
+}
+
+func handleRequests(p *tea.Program, sess session, reqCh <-chan interface{}, canceler *requestCanceler, timeout time.Duration) {
+	for req := range reqCh {
+		id, req := untagRequest(req)
+
+		switch req.(type) {
+		case lambdaListRefreshReq, lambdaDetailRefreshReq:
+			go handleRefreshRequest(p, sess, req, timeout)
+
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		// Exports may fetch a large number of pages and only end when
+		// cancelled by the user.
+		if _, ok := req.(exportReq); ok {
+			ctx, cancel = context.WithCancel(context.Background())
+		}
+		canceler.set(cancel)
+
+		msg := handleRequest(ctx, &sess, req)
+		canceler.set(nil)
+
+		// The model has already moved on from requests cancelled by the user.
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel()
+			log.Printf("[Info] cancelled %T", req)
+
+			continue
+		}
+
+		if _, ok := msg.(errMsg); ok && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			msg = errMsg{fmt.Errorf("request timed out after %s", timeout)}
+		}
+		cancel()
+
+		if msg != nil {
+			p.Send(tagResponse(id, msg))
+		}
+	}
+}
+
+// handleRequest performs a request sent by the model and returns the message
+// to respond with. Requests switching the session update sess.
+func handleRequest(ctx context.Context, sess *session, req interface{}) tea.Msg {
+	switch req := req.(type) {
+	case logStreamReq:
+		streams, nextToken, err := getLogStreams(ctx, sess.cloudwatchClient(req.region), req.logGroup, "", req.versions)
+		if err != nil {
+			return logError(err)
+		}
+
+		return logStreamMsg{items: streams, nextToken: nextToken}
+
+	case logStreamPageReq:
+		streams, nextToken, err := getLogStreams(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.nextToken, req.versions)
+		if err != nil {
+			return logError(err)
+		}
+
+		return logStreamPageMsg{
+			region:    req.region,
+			logGroup:  req.logGroup,
+			versions:  req.versions,
+			items:     streams,
+			nextToken: nextToken,
+		}
+
+	case logEventReq:
+		page, err := getLogEvents(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.logStream, "", time.Time{})
+		if err != nil {
+			return logError(err)
+		}
+
+		return logEventMsg{events: page.events, forwardToken: page.forwardToken, backwardToken: page.backwardToken}
+
+	case logEventPageReq:
+		page, err := getLogEvents(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.logStream, req.backwardToken, time.Time{})
+		if err != nil {
+			return logError(err)
+		}
+
+		return logEventPageMsg{region: req.region, logGroup: req.logGroup, logStream: req.logStream, events: page.events, backwardToken: page.backwardToken}
+
+	case logEventFollowReq:
+		events, forwardToken, err := getNewLogEvents(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.logStream, req.forwardToken)
+		if err != nil {
+			return logError(err)
+		}
+
+		return logEventFollowMsg{logStream: req.logStream, events: events, forwardToken: forwardToken}
+
+	case exportReq:
+		if err := exportLogEvents(ctx, sess.cloudwatchClient(req.region), req); err != nil {
+			return logError(err)
+		}
+
+		return exportMsg{path: req.path, events: req.progress.Load()}
+
+	case lambdaDetailReq:
+		lambdaInfo, err := getLambdaInfo(ctx, sess.lambdaClient(req.region), req.name, req.qualifier)
+		if err != nil {
+			return logError(err)
+		}
+
+		sess.cache.storeDetails(sess.cacheAccount(), cmp.Or(req.region, sess.region), lambdaInfo)
+
+		return lambdaDetailMsg{info: lambdaInfo}
+
+	case lambdaDetailRefreshReq:
+		lambdaInfo, err := getLambdaInfo(ctx, sess.lambdaClient(req.region), req.name, "")
+		if err != nil {
+			logError(err)
 
-			p.Send(logStreamMsg{items: streams})
+			return lambdaDetailRefreshFailedMsg{account: sess.cacheAccount(), region: req.region, name: req.name, err: err}
+		}
+
+		sess.cache.storeDetails(sess.cacheAccount(), cmp.Or(req.region, sess.region), lambdaInfo)
 
-		case logEventReq:
-			events, err := getLogEvents(context.Background(), cwClient, msg.logGroup, msg.logStream)
-			if err != nil {
-				log.Printf("[Error] %v", err)
-				p.Send(errMsg{err})
+		return lambdaDetailRefreshMsg{account: sess.cacheAccount(), region: req.region, info: lambdaInfo}
 
-				continue
-			}
+	case triggersReq:
+		mappings, permissions, err := getLambdaTriggers(ctx, sess.lambdaClient(req.region), req.name, req.qualifier)
+		if err != nil {
+			return logError(err)
+		}
 
-			p.Send(logEventMsg{events: events})
+		return triggersMsg{mappings: mappings, permissions: permissions}
+
+	case concurrencyReq:
+		info, err := getConcurrency(ctx, sess.lambdaClient(req.region), req.name)
+		if err != nil {
+			return logError(err)
+		}
 
-		case lambdaDetailReq:
-			lambdaInfo, err := getLambdaInfo(context.Background(), lambdaClient, msg.name)
-			if err != nil {
-				log.Printf("[Error] %v", err)
-				p.Send(errMsg{err})
+		return concurrencyMsg{info: info}
+
+	case concurrencyUpdateReq:
+		c := sess.lambdaClient(req.region)
+
+		var err error
+		if req.change.qualifier == "" {
+			err = setReservedConcurrency(ctx, c, req.name, req.change.value)
+		} else {
+			err = setProvisionedConcurrency(ctx, c, req.name, req.change.qualifier, req.change.value)
+		}
+		if err != nil {
+			return logError(err)
+		}
 
-				continue
-			}
-			p.Send(lambdaDetailMsg{info: lambdaInfo})
+		info, err := getConcurrency(ctx, c, req.name)
+		if err != nil {
+			return logError(err)
 		}
+
+		sess.cache.storeReservedConcurrency(sess.cacheAccount(), cmp.Or(req.region, sess.region), req.name, info.reserved)
+
+		return concurrencyMsg{info: info}
+
+	case envUpdateReq:
+		c := sess.lambdaClient(req.region)
+		if err := updateEnvVars(ctx, c, req.name, req.revisionId, req.vars); err != nil {
+			return logError(err)
+		}
+
+		lambdaInfo, err := getLambdaInfo(ctx, c, req.name, "")
+		if err != nil {
+			return logError(err)
+		}
+
+		sess.cache.storeDetails(sess.cacheAccount(), cmp.Or(req.region, sess.region), lambdaInfo)
+
+		return lambdaDetailMsg{info: lambdaInfo}
+
+	case metricsReq:
+		series, err := getLambdaMetrics(ctx, sess.metricsClient(req.region), req.name, req.start, req.end, req.period)
+		if err != nil {
+			return logError(err)
+		}
+
+		return metricsMsg{series: series}
+
+	case analyticsReq:
+		info, err := getLambdaInfo(ctx, sess.lambdaClient(req.region), req.name, "")
+		if err != nil {
+			return logError(err)
+		}
+
+		invocations, streams, err := getRecentInvocations(ctx, sess.cloudwatchClient(req.region), req.logGroup)
+		if err != nil {
+			return logError(err)
+		}
+
+		return analyticsMsg{stats: analyzeInvocations(invocations, info.memory), streams: streams}
+
+	case versionsReq:
+		versions, aliases, err := getLambdaVersions(ctx, sess.lambdaClient(req.region), req.name)
+		if err != nil {
+			return logError(err)
+		}
+
+		return versionsMsg{versions: versions, aliases: aliases}
+
+	case invokeReq:
+		result, err := invokeLambda(ctx, sess.lambdaClient(req.region), req.name, req.payload)
+		if err != nil {
+			return logError(err)
+		}
+
+		return invokeMsg{result: result}
+
+	case insightsReq:
+		result, err := runInsightsQuery(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.query, req.start, req.end)
+		if err != nil {
+			return logError(err)
+		}
+
+		return insightsMsg{result: result}
+
+	case filterReq:
+		events, truncated, err := filterLogEvents(ctx, sess.cloudwatchClient(req.region), req.logGroup, req.pattern, req.start, req.end, filterLimit)
+		if err != nil {
+			return logError(err)
+		}
+
+		return filterMsg{events: events, truncated: truncated}
+
+	case profileListReq:
+		profiles, err := listProfiles()
+		if err != nil {
+			return logError(err)
+		}
+
+		return profileListMsg{profiles: profiles}
+
+	case sessionReq:
+		newSess, err := newSession(ctx, req.profile, req.region)
+		if err != nil {
+			return logError(err)
+		}
+
+		items, err := listLambdaFunctions(ctx, newSess, req.regions)
+		if err != nil {
+			return logError(err)
+		}
+
+		newSess.cache = sess.cache
+		*sess = newSess
+		sess.cache.storeFunctions(sess.cacheAccount(), sess.region, req.regions, items)
+
+		return sessionMsg{profile: sess.profile, region: sess.region, accountId: sess.accountId, items: items}
+
+	case lambdaListReq:
+		items, err := listLambdaFunctions(ctx, *sess, req.regions)
+		if err != nil {
+			return logError(err)
+		}
+
+		sess.cache.storeFunctions(sess.cacheAccount(), sess.region, req.regions, items)
+
+		return lambdaListMsg{items: items, multiRegion: len(req.regions) > 0}
+
+	case lambdaListRefreshReq:
+		items, err := listLambdaFunctions(ctx, *sess, req.regions)
+		if err != nil {
+			logError(err)
+
+			return lambdaListRefreshFailedMsg{account: sess.cacheAccount(), region: sess.region, regions: req.regions, err: err}
+		}
+
+		sess.cache.storeFunctions(sess.cacheAccount(), sess.region, req.regions, items)
+
+		return lambdaListRefreshMsg{account: sess.cacheAccount(), region: sess.region, regions: req.regions, items: items}
 	}
+
+	return nil
+}
+
+func logError(err error) tea.Msg {
+	log.Printf("[Error] %v", err)
+
+	return errMsg{err}
 }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// A maskMode determines which environment variable values are masked.
+type maskMode string
+
+const (
+	maskAll     maskMode = "all"
+	maskSecrets maskMode = "secrets"
+	maskNone    maskMode = "none"
+)
+
+// maskedValue replaces masked values. It has a fixed length so that the
+// length of a value is not revealed either.
+const maskedValue = "••••••••"
+
+// defaultSecretPatterns match the keys of environment variables that are
+// masked in secrets mode.
+var defaultSecretPatterns = []string{"*SECRET*", "*TOKEN*", "*PASSWORD*", "*API_KEY*", "*PRIVATE_KEY*", "*CREDENTIAL*"}
+
+// An envMasker decides which environment variable values are masked.
+type envMasker struct {
+	mode maskMode
+	// patterns are glob patterns matched against upper case keys in
+	// secrets mode.
+	patterns []string
+}
+
+// newEnvMasker validates the given mode and patterns.
+func newEnvMasker(mode string, patterns []string) (envMasker, error) {
+	switch maskMode(mode) {
+	case maskAll, maskSecrets, maskNone:
+	default:
+		return envMasker{}, fmt.Errorf("invalid mask mode %q, expected one of all, secrets or none", mode)
+	}
+
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return envMasker{}, fmt.Errorf("invalid secret pattern %q: %w", pattern, err)
+		}
+	}
+
+	return envMasker{mode: maskMode(mode), patterns: patterns}, nil
+}
+
+// masks reports whether the value of the given key is masked.
+func (e envMasker) masks(key string) bool {
+	switch e.mode {
+	case maskAll:
+		return true
+	case maskSecrets:
+		for _, pattern := range e.patterns {
+			if ok, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(key)); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// envValue returns the value of an environment variable as it is to be shown,
+// which is masked unless the variable has been revealed.
+func (m model) envValue(key string, value string) string {
+	if m.envRevealAll || m.envRevealed[key] || !m.envMasker.masks(key) {
+		return value
+	}
+
+	return maskedValue
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type metricsReq struct {
+	region string
+	name   string
+	start  time.Time
+	end    time.Time
+	period time.Duration
+}
+
+type metricsMsg struct {
+	series []metricSeries
+}
+
+// metricsRanges are the selectable time windows of the metrics view, ending
+// at the time the metrics are fetched.
+var metricsRanges = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"1h", time.Hour},
+	{"3h", 3 * time.Hour},
+	{"12h", 12 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"3d", 3 * 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+}
+
+// metricsPoints is the number of periods a time window is divided into.
+const metricsPoints = 60
+
+var sparklineBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var (
+	metricNameStyle    = lipgloss.NewStyle().Bold(true).MarginLeft(1)
+	metricSummaryStyle = lipgloss.NewStyle()
+	sparklineStyle     = lipgloss.NewStyle().MarginLeft(1)
+
+	// sparklineStyles override sparklineStyle for metrics that indicate
+	// problems.
+	sparklineStyles = map[string]lipgloss.Style{
+		"Errors":    sparklineStyle,
+		"Throttles": sparklineStyle,
+	}
+)
+
+// metricsPeriod returns the period metrics are aggregated over, which must be
+// a multiple of a minute.
+func metricsPeriod(window time.Duration) time.Duration {
+	return max((window / metricsPoints).Truncate(time.Minute), time.Minute)
+}
+
+// requestMetrics fetches the metrics of the metrics target for the selected
+// time window.
+func (m *model) requestMetrics() tea.Cmd {
+	window := metricsRanges[m.metricsRange].duration
+	end := time.Now().Truncate(time.Minute)
+
+	select {
+	case m.reqCh <- m.tag(metricsReq{
+		region: m.metricsTarget.region,
+		name:   m.metricsTarget.name,
+		start:  end.Add(-window),
+		end:    end,
+		period: metricsPeriod(window),
+	}):
+		return m.startLoading()
+	default:
+		return nil
+	}
+}
+
+func (m model) viewMetricsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			m.activeView = viewLambda
+		case key.Matches(msg, m.keys.refresh):
+			cmd = m.requestMetrics()
+		case key.Matches(msg, m.keys.next):
+			m.metricsRange = (m.metricsRange + 1) % len(metricsRanges)
+			cmd = m.requestMetrics()
+		case key.Matches(msg, m.keys.prev):
+			m.metricsRange = (m.metricsRange + len(metricsRanges) - 1) % len(metricsRanges)
+			cmd = m.requestMetrics()
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvMetricsMsg(msg metricsMsg) {
+	m.metricsSeries = msg.series
+	m.activeView = viewMetrics
+	m.loading = false
+}
+
+func (m model) metricsView() string {
+	ranges := make([]string, 0, len(metricsRanges))
+	for i, r := range metricsRanges {
+		style := insightsRangeStyle
+		if i == m.metricsRange {
+			style = insightsSelectedRangeStyle
+		}
+
+		ranges = append(ranges, style.Render(r.label))
+	}
+
+	width := max(m.winWidth-docStyle.GetHorizontalFrameSize()-sparklineStyle.GetHorizontalFrameSize(), 1)
+	rows := []string{
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Metrics - Function \"%s\" - %s", m.metricsTarget.name, m.sessionTitle())),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, append([]string{helpStyle.Render("Last ")}, ranges...)...),
+	}
+
+	for _, series := range m.metricsSeries {
+		style, ok := sparklineStyles[series.name]
+		if !ok {
+			style = sparklineStyle
+		}
+
+		rows = append(
+			rows,
+			"",
+			metricNameStyle.Render(series.name)+"  "+metricSummaryStyle.Render(metricSummary(series)),
+			style.Render(sparkline(series.values, width)),
+		)
+	}
+
+	rows = append(rows, "", helpLine(
+		keyHelp(m.keys.next, "next range"),
+		keyHelp(m.keys.prev, "prev range"),
+		keyHelp(m.keys.refresh, ""),
+		keyHelp(m.keys.back, ""),
+	))
+
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// metricSummary describes the minimum, average and 99th percentile of the
+// periods with data of a series. The queried 99th percentile is preferred
+// over that of the periods.
+func metricSummary(series metricSeries) string {
+	if len(series.points) == 0 {
+		return "no data"
+	}
+
+	sorted := slices.Sorted(slices.Values(series.points))
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+
+	p99 := percentile(sorted, 0.99)
+	if series.p99 != nil {
+		p99 = *series.p99
+	}
+
+	return fmt.Sprintf(
+		"min %s • avg %s • p99 %s",
+		formatMetricValue(sorted[0], series.unit),
+		formatMetricValue(sum/float64(len(sorted)), series.unit),
+		formatMetricValue(p99, series.unit),
+	)
+}
+
+func formatMetricValue(v float64, unit string) string {
+	s := fmt.Sprintf("%.2f", v)
+	if v == math.Trunc(v) {
+		s = fmt.Sprintf("%.0f", v)
+	}
+
+	if unit != "" {
+		s += " " + unit
+	}
+
+	return s
+}
+
+// sparkline renders values as a single line of block characters, scaled to
+// the largest value. Series longer than width are reduced to the maximum of
+// adjacent values, shorter series are stretched.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		size := int(math.Ceil(float64(len(values)) / float64(width)))
+		reduced := make([]float64, 0, width)
+		for chunk := range slices.Chunk(values, size) {
+			reduced = append(reduced, slices.Max(chunk))
+		}
+		values = reduced
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	// Short series are stretched to make use of the available width.
+	if repeat := width / len(values); repeat > 1 {
+		stretched := make([]float64, 0, len(values)*repeat)
+		for _, v := range values {
+			for range repeat {
+				stretched = append(stretched, v)
+			}
+		}
+		values = stretched
+	}
+
+	highest := slices.Max(values)
+
+	var b strings.Builder
+	for _, v := range values {
+		// Any non-zero value is drawn above the baseline.
+		i := 0
+		if v > 0 && highest > 0 {
+			i = max(int(math.Round(v/highest*float64(len(sparklineBlocks)-1))), 1)
+		}
+
+		b.WriteRune(sparklineBlocks[i])
+	}
+
+	return b.String()
+}
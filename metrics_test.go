@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		width  int
+		want   string
+	}{
+		{[]float64{0, 1, 4, 8}, 4, "▁▂▅█"},
+		{[]float64{0, 0}, 4, "▁▁▁▁"},
+		{[]float64{1, 8}, 6, "▂▂▂███"},
+		{[]float64{1, 2, 8, 0, 4, 4}, 3, "▃█▅"},
+		{nil, 10, ""},
+	}
+
+	for _, test := range tests {
+		if got := sparkline(test.values, test.width); got != test.want {
+			t.Errorf("sparkline(%v, %d) = %q, want %q", test.values, test.width, got, test.want)
+		}
+	}
+}
+
+func TestMetricSummary(t *testing.T) {
+	// Periods without data are left out.
+	series := metricSeries{name: "Duration", unit: "ms", values: []float64{3, 0, 1, 2, 0, 10}, points: []float64{3, 1, 2, 10}}
+
+	if got, want := metricSummary(series), "min 1 ms • avg 4 ms • p99 10 ms"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	series.p99 = ptr(25.5)
+	if got, want := metricSummary(series), "min 1 ms • avg 4 ms • p99 25.50 ms"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if got := metricSummary(metricSeries{values: []float64{0, 0}}); got != "no data" {
+		t.Fatalf("got %q for a series without data", got)
+	}
+}
+
+func TestMetricsPeriod(t *testing.T) {
+	for window, want := range map[time.Duration]time.Duration{
+		time.Hour:          time.Minute,
+		24 * time.Hour:     24 * time.Minute,
+		7 * 24 * time.Hour: 168 * time.Minute,
+		10 * time.Minute:   time.Minute,
+	} {
+		if got := metricsPeriod(window); got != want {
+			t.Errorf("metricsPeriod(%s) = %s, want %s", window, got, want)
+		}
+	}
+}
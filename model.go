@@ -2,10 +2,18 @@ package main
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,69 +22,231 @@ import (
 
 type model struct {
 	accountId       string
+	profile         string
+	region          string
+	selectedProfile string
 	activeView      string
 	activeLambda    string
+	// The regions of the active lambda and log group are empty unless
+	// functions of multiple regions are listed.
+	activeLambdaRegion string
+	activeLogRegion    string
+	// activeLambdaQualifier is the version or alias the detail view shows,
+	// empty for $LATEST.
+	activeLambdaQualifier string
+	multiRegion           bool
+	// listRegions are the regions listed in multi-region mode.
+	listRegions     []string
 	activeLogGroup  string
 	activeLogStream string
-	err             string
-	loading         bool
-	lambdas         list.Model
-	lambdaDetail    viewport.Model
-	logStreams      list.Model
-	logEvents       viewport.Model
-	spinner         spinner.Model
-	reqCh           chan<- interface{}
-	winHeight       int
-	winWidth        int
+	// logStreamsNextToken and logEventsBackwardToken are empty once all log
+	// streams or events have been loaded.
+	logStreamsNextToken string
+	// logStreamVersions restricts the log streams to the versions of
+	// logStreamQualifier, unless empty.
+	logStreamQualifier     string
+	logStreamVersions      []string
+	loadingMoreLogStreams  bool
+	logEventsBackwardToken string
+	loadingMoreLogEvents   bool
+	invokeTarget           string
+	invokeRegion           string
+	insightsTarget         lambdaItem
+	versionsTarget         lambdaItem
+	// activeInvocation is the invocation whose log events are shown.
+	activeInvocation invocation
+	metricsTarget    lambdaItem
+	analyticsTarget  lambdaItem
+	// filterFocus is the index of the focused input of the search form.
+	filterFocus int
+	// filterEvents are the events matched by the log group search between
+	// filterStart and filterEnd.
+	filterEvents    [][]string
+	filterStart     time.Time
+	filterEnd       time.Time
+	filterTruncated bool
+	metricsRange    int
+	metricsSeries   []metricSeries
+	analytics       invocationStats
+	// analyticsStreams is the number of log streams analytics were read from.
+	analyticsStreams int
+	// requestId is the id of the last tagged request.
+	requestId int
+	// lastClick is the last click on a list item, to detect double clicks.
+	lastClick mouseClick
+	// mouseDisabled is set while the mouse is not captured.
+	mouseDisabled bool
+	// lambdaDetailInfo holds the details shown in the detail view.
+	lambdaDetailInfo lambdaInfo
+	envMasker        envMasker
+	// envCursor is the index of the selected environment variable, whose
+	// value is revealed by envRevealed.
+	envCursor    int
+	envRevealed  map[string]bool
+	envRevealAll bool
+	// envVars are the edited environment variables, which differ from the
+	// current ones by envChanges.
+	envVars    map[string]string
+	envChanges []envChange
+	// envEditorRevealed is set once the masked values in the editor have
+	// been revealed, envDiffRevealed once all values of the diff have.
+	envEditorRevealed bool
+	envDiffRevealed   bool
+	concurrency       concurrencyInfo
+	// concurrencyCursor selects the reserved concurrency at zero and the
+	// provisioned concurrency configs after it.
+	concurrencyCursor int
+	concurrencyPrompt string
+	concurrencyChange concurrencyChange
+	insightsRange     int
+	following         bool
+	followId          int
+	// followAutoScroll is unset while the user has scrolled away from the
+	// bottom of the log events.
+	followAutoScroll bool
+	logEventRows     [][]string
+	// logEventLines caches the rendered log event rows and logEventOffsets
+	// holds the line each row starts at within the viewport.
+	logEventLines    []string
+	logEventOffsets  []int
+	logSearching     bool
+	logSearchRegex   bool
+	logSearchExpr    *regexp.Regexp
+	logSearchErr     error
+	logSearchMatches []int
+	logSearchCurrent int
+	logFormat        logFormat
+	exporting        bool
+	// exportProgress counts the exported events while an export is running.
+	exportProgress *atomic.Int64
+	exportStatus   string
+	// logEventFormats overrides logFormat for single events.
+	logEventFormats       map[int]logFormat
+	logEventsForwardToken string
+	err                   string
+	keys                  keyMap
+	cache                 *lambdaCache
+	// lambdasCachedAt and lambdaDetailCachedAt are the times the shown
+	// functions and details were cached, which are zero once fetched.
+	lambdasCachedAt      time.Time
+	lambdaDetailCachedAt time.Time
+	// lambdasRefreshFailed and detailRefreshFailed are set if the
+	// background refresh of the shown cached data failed.
+	lambdasRefreshFailed bool
+	detailRefreshFailed  bool
+	loading              bool
+	loadingSince         time.Time
+	beforeLoading        viewState
+	canceler             *requestCanceler
+	lambdas              list.Model
+	lambdaDetail         viewport.Model
+	triggers             viewport.Model
+	logStreams           list.Model
+	versions             list.Model
+	invocations          list.Model
+	invocationEvents     viewport.Model
+	profiles             list.Model
+	regions              list.Model
+	logEvents            viewport.Model
+	logSearchInput       textinput.Model
+	exportInput          textinput.Model
+	concurrencyInput     textinput.Model
+	filterInputs         []textinput.Model
+	filterOutput         viewport.Model
+	invokePayload        textarea.Model
+	envEditor            textarea.Model
+	invokeOutput         viewport.Model
+	insightsQuery        textarea.Model
+	insightsOutput       viewport.Model
+	spinner              spinner.Model
+	reqCh                chan<- interface{}
+	winHeight            int
+	winWidth             int
 }
 
 const (
-	viewLambda       = "lambda"
-	viewLambdaDetail = "lambdadetail"
-	viewLogStream    = "logStream"
-	viewLogEvent     = "logEvent"
+	viewLambda         = "lambda"
+	viewLambdaDetail   = "lambdadetail"
+	viewLogStream      = "logStream"
+	viewLogEvent       = "logEvent"
+	viewInvoke         = "invoke"
+	viewInvokeResult   = "invokeResult"
+	viewProfile        = "profile"
+	viewRegion         = "region"
+	viewInsights       = "insights"
+	viewInsightsResult = "insightsResult"
+	viewVersions       = "versions"
+	viewMetrics        = "metrics"
+	viewAnalytics      = "analytics"
+	viewFilter         = "filter"
+	viewFilterResult   = "filterResult"
+	viewEnvEdit        = "envEdit"
+	viewEnvDiff        = "envDiff"
+	viewTriggers       = "triggers"
+
+	viewInvocations      = "invocations"
+	viewInvocationEvents = "invocationEvents"
+
+	viewConcurrency        = "concurrency"
+	viewConcurrencyConfirm = "concurrencyConfirm"
 )
 
 var (
 	logEventStyle          = lipgloss.NewStyle().AlignVertical(lipgloss.Center)
-	logEventTimestampStyle = logEventStyle.Bold(true).
-				Padding(1).
-				Foreground(lipgloss.Color("#3275C4"))
+	logEventTimestampStyle = logEventStyle.Bold(true).Padding(1)
 
 	lambdaDetailFieldNameStyle = lipgloss.
 					NewStyle().
 					Bold(true).
-					Background(lipgloss.Color("#192a4a")).
 					PaddingLeft(2).
 					PaddingRight(2)
 
 	lambdaDetailFieldValueStyle = lipgloss.NewStyle().
-					Background(lipgloss.Color("235")).
 					PaddingLeft(2).
 					PaddingRight(2)
 
+	lambdaDetailSelectedFieldNameStyle = lambdaDetailFieldNameStyle
+
 	lambdaDetailTitleStyle = lipgloss.NewStyle().
 				Bold(true).
 				MarginLeft(1).
 				AlignHorizontal(lipgloss.Left)
 )
 
+// A log stream request lists the streams of a log group, optionally only
+// those of the given function versions.
 type logStreamReq struct {
+	region   string
 	logGroup string
+	versions []string
 }
 
 type logEventReq struct {
+	region    string
 	logGroup  string
 	logStream string
 }
 
 type lambdaDetailReq struct {
-	name string
+	region    string
+	name      string
+	qualifier string
+}
+
+// A lambda list request lists the functions of the given regions, or of the
+// session region if none are given.
+type lambdaListReq struct {
+	regions []string
 }
 
 var (
 	docStyle     = lipgloss.NewStyle().Margin(1, 2)
 	spinnerStyle = lipgloss.NewStyle().Align(lipgloss.Center)
+
+	errorStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+
+	helpStyle           = lipgloss.NewStyle().MarginLeft(1)
+	logEventStatusStyle = lipgloss.NewStyle().PaddingRight(1)
 )
 
 func (m model) Init() tea.Cmd {
@@ -84,12 +254,28 @@ func (m model) Init() tea.Cmd {
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if tagged, ok := msg.(taggedMsg); ok {
+		// The response to a cancelled request may arrive after the model
+		// returned to the view it was sent from.
+		if !m.loading || tagged.id != m.beforeLoading.requestId {
+			return m, nil
+		}
+
+		msg = tagged.msg
+	}
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+		if key.Matches(msg, m.keys.forceQuit) || (key.Matches(msg, m.keys.quit) && !m.capturesInput()) {
 			return m, tea.Quit
 		}
 
+		if key.Matches(msg, m.keys.mouse) && !(m.capturesInput() && insertsText(msg)) {
+			cmd := m.toggleMouse()
+
+			return m, cmd
+		}
+
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
 
@@ -97,10 +283,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.winWidth = msg.Width
 		m.lambdas.SetSize(msg.Width-h, msg.Height-v)
 		m.logStreams.SetSize(msg.Width-h, msg.Height-v)
+		m.versions.SetSize(msg.Width-h, msg.Height-v)
+		m.invocations.SetSize(msg.Width-h, msg.Height-v)
+		m.profiles.SetSize(msg.Width-h, msg.Height-v)
+		m.regions.SetSize(msg.Width-h, msg.Height-v)
 		m.logEvents.Width = msg.Width
-		m.logEvents.Height = msg.Height
+		m.logEvents.Height = msg.Height - lipgloss.Height(logEventStatusStyle.Render())
+		m.renderLogEvents()
+		m.filterOutput.Width = msg.Width
+		m.filterOutput.Height = msg.Height - lipgloss.Height(logEventStatusStyle.Render())
+		m.renderFilterEvents()
+		m.invocationEvents.Width = msg.Width
+		m.invocationEvents.Height = msg.Height - lipgloss.Height(logEventStatusStyle.Render())
 		m.lambdaDetail.Width = msg.Width
 		m.lambdaDetail.Height = msg.Height
+		m.triggers.Width = msg.Width
+		m.triggers.Height = msg.Height
+		m.invokePayload.SetWidth(msg.Width - h)
+		m.invokePayload.SetHeight(msg.Height - v - 4)
+		m.envEditor.SetWidth(msg.Width - h)
+		m.envEditor.SetHeight(msg.Height - v - 4)
+		m.invokeOutput.Width = msg.Width
+		m.invokeOutput.Height = msg.Height
+		m.insightsQuery.SetWidth(msg.Width - h)
+		m.insightsQuery.SetHeight(msg.Height - v - 8)
+		m.insightsOutput.Width = msg.Width
+		m.insightsOutput.Height = msg.Height
 
 	case lambdaDetailMsg:
 		m.onRcvLambdaDetailMsg(msg)
@@ -111,9 +319,90 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case logEventMsg:
 		m.onRcvLogEventMsg(msg)
 
+	case invokeMsg:
+		m.onRcvInvokeMsg(msg)
+
+	case insightsMsg:
+		m.onRcvInsightsMsg(msg)
+
+	case lambdaListMsg:
+		m.onRcvLambdaListMsg(msg)
+
+	case lambdaListRefreshMsg:
+		m.onRcvLambdaListRefreshMsg(msg)
+
+		return m, nil
+
+	case lambdaDetailRefreshMsg:
+		m.onRcvLambdaDetailRefreshMsg(msg)
+
+		return m, nil
+
+	case lambdaListRefreshFailedMsg:
+		m.onRcvLambdaListRefreshFailedMsg(msg)
+
+		return m, nil
+
+	case lambdaDetailRefreshFailedMsg:
+		m.onRcvLambdaDetailRefreshFailedMsg(msg)
+
+		return m, nil
+
+	case versionsMsg:
+		m.onRcvVersionsMsg(msg)
+
+	case metricsMsg:
+		m.onRcvMetricsMsg(msg)
+
+	case analyticsMsg:
+		m.onRcvAnalyticsMsg(msg)
+
+	case filterMsg:
+		m.onRcvFilterMsg(msg)
+
+	case triggersMsg:
+		m.onRcvTriggersMsg(msg)
+
+	case concurrencyMsg:
+		m.onRcvConcurrencyMsg(msg)
+
+	case profileListMsg:
+		m.onRcvProfileListMsg(msg)
+
+	case sessionMsg:
+		m.onRcvSessionMsg(msg)
+
+	case logEventFollowMsg:
+		m.onRcvLogEventFollowMsg(msg)
+
+		return m, nil
+
+	case logStreamPageMsg:
+		m.onRcvLogStreamPageMsg(msg)
+
+		return m, nil
+
+	case logEventPageMsg:
+		m.onRcvLogEventPageMsg(msg)
+
+		return m, nil
+
+	case exportMsg:
+		m.onRcvExportMsg(msg)
+
+	case followTickMsg:
+		if msg.id != m.followId {
+			return m, nil
+		}
+
+		return m, m.followLogEvents()
+
 	case errMsg:
-		m.err = wrapString(msg.err.Error(), m.winWidth*7/10)
-		m.err += "\n\n Press enter/esc to continue"
+		m.stopFollow()
+		m.loadingMoreLogStreams = false
+		m.loadingMoreLogEvents = false
+		m.exportProgress = nil
+		m.showError(msg.err.Error())
 	}
 
 	if m.err != "" {
@@ -121,7 +410,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if m.loading {
-		return m, nil
+		return m.viewLoadingUpdate(msg)
+	}
+
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		if msg = m.onMouse(mouse); msg == nil {
+			return m, nil
+		}
 	}
 
 	switch m.activeView {
@@ -133,13 +428,55 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.viewLogStreamUpdate(msg)
 	case viewLogEvent:
 		return m.viewLogEventUpdate(msg)
+	case viewInvoke:
+		return m.viewInvokeUpdate(msg)
+	case viewInvokeResult:
+		return m.viewInvokeResultUpdate(msg)
+	case viewProfile:
+		return m.viewProfileUpdate(msg)
+	case viewRegion:
+		return m.viewRegionUpdate(msg)
+	case viewInsights:
+		return m.viewInsightsUpdate(msg)
+	case viewInsightsResult:
+		return m.viewInsightsResultUpdate(msg)
+	case viewVersions:
+		return m.viewVersionsUpdate(msg)
+	case viewMetrics:
+		return m.viewMetricsUpdate(msg)
+	case viewAnalytics:
+		return m.viewAnalyticsUpdate(msg)
+	case viewFilter:
+		return m.viewFilterUpdate(msg)
+	case viewFilterResult:
+		return m.viewFilterResultUpdate(msg)
+	case viewTriggers:
+		return m.viewTriggersUpdate(msg)
+	case viewInvocations:
+		return m.viewInvocationsUpdate(msg)
+	case viewInvocationEvents:
+		return m.viewInvocationEventsUpdate(msg)
+	case viewConcurrency:
+		return m.viewConcurrencyUpdate(msg)
+	case viewConcurrencyConfirm:
+		return m.viewConcurrencyConfirmUpdate(msg)
+	case viewEnvEdit:
+		return m.viewEnvEditUpdate(msg)
+	case viewEnvDiff:
+		return m.viewEnvDiffUpdate(msg)
 	}
 
 	panic("unknown update function for view " + m.activeView)
 }
 
+// showError shows an error message until it is dismissed.
+func (m *model) showError(message string) {
+	m.err = wrapString(message, m.winWidth*7/10)
+	m.err += fmt.Sprintf("\n\n Press %s/%s to continue", m.keys.choose.Help().Key, m.keys.back.Help().Key)
+}
+
 func (m model) viewErrUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if msg, ok := msg.(tea.KeyMsg); ok && (msg.String() == "enter" || msg.String() == "esc") {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.choose, m.keys.back) {
 		m.err = ""
 		m.loading = false
 	}
@@ -165,38 +502,96 @@ func (m model) viewLambdaUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, m.keys.choose):
 			if !hasSelectedItem {
 				break
 			}
 
-			if selectedItem.name == m.activeLambda {
-				m.activeView = viewLambdaDetail
-				cmd = nil
+			cmd = m.openLambdaDetail(selectedItem)
+		case key.Matches(msg, m.keys.logStreams):
+			if !hasSelectedItem {
 				break
 			}
 
 			select {
-			case m.reqCh <- lambdaDetailReq{name: selectedItem.name}:
-				m.loading = true
-				cmd = m.spinner.Tick
-				m.activeLambda = selectedItem.name
+			case m.reqCh <- m.tag(logStreamReq{region: selectedItem.region, logGroup: selectedItem.logGroup}):
+				cmd = m.startLoading()
+
+				if selectedItem.logGroup != m.activeLogGroup || selectedItem.region != m.activeLogRegion {
+					m.activeLogStream = ""
+				}
+
+				m.activeLogGroup = selectedItem.logGroup
+				m.activeLogRegion = selectedItem.region
+				m.logStreamQualifier = ""
+				m.logStreamVersions = nil
 			default:
 			}
-		case "l":
+		case key.Matches(msg, m.keys.invoke):
+			if !hasSelectedItem {
+				break
+			}
+
+			if selectedItem.name != m.invokeTarget || selectedItem.region != m.invokeRegion {
+				m.invokePayload.SetValue("{}")
+			}
+
+			m.invokeTarget = selectedItem.name
+			m.invokeRegion = selectedItem.region
+			m.activeView = viewInvoke
+			cmd = m.invokePayload.Focus()
+		case key.Matches(msg, m.keys.insights):
 			if !hasSelectedItem {
 				break
 			}
 
+			m.insightsTarget = selectedItem
+			m.activeView = viewInsights
+			cmd = m.insightsQuery.Focus()
+		case key.Matches(msg, m.keys.metrics):
+			if !hasSelectedItem {
+				break
+			}
+
+			m.metricsTarget = selectedItem
+			cmd = m.requestMetrics()
+		case key.Matches(msg, m.keys.analytics):
+			if !hasSelectedItem {
+				break
+			}
+
+			m.analyticsTarget = selectedItem
+			cmd = m.requestAnalytics()
+		case key.Matches(msg, m.keys.multiRegion):
+			regions := m.listRegions
+			if m.multiRegion {
+				regions = nil
+			} else if len(regions) == 0 {
+				m.showError("No regions configured for multi-region mode, start with -regions")
+				break
+			}
+
 			select {
-			case m.reqCh <- logStreamReq{logGroup: selectedItem.logGroup}:
-				m.activeLogGroup = selectedItem.logGroup
-				m.loading = true
-				cmd = m.spinner.Tick
+			case m.reqCh <- m.tag(lambdaListReq{regions: regions}):
+				cmd = m.startLoading()
+			default:
+			}
+		case key.Matches(msg, m.keys.refreshAll):
+			select {
+			case m.reqCh <- m.tag(lambdaListReq{regions: m.lambdaListRegions()}):
+				cmd = m.startLoading()
+				m.cache.dropDetails(m.cacheAccount(), m.region, m.lambdaListRegions())
+				m.activeLambda = ""
+			default:
+			}
+		case key.Matches(msg, m.keys.profile):
+			select {
+			case m.reqCh <- m.tag(profileListReq{}):
+				cmd = m.startLoading()
 			default:
 			}
-		case "esc":
+		case key.Matches(msg, m.keys.back):
 			m.lambdas.ResetFilter()
 			cmd = nil
 		}
@@ -204,11 +599,83 @@ func (m model) viewLambdaUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openLambdaDetail shows the details of the $LATEST version of a function,
+// which are requested unless already shown or cached.
+func (m *model) openLambdaDetail(item lambdaItem) tea.Cmd {
+	if item.name == m.activeLambda && item.region == m.activeLambdaRegion && m.activeLambdaQualifier == "" {
+		m.activeView = viewLambdaDetail
+
+		return nil
+	}
+
+	if m.showCachedLambdaDetail(item) {
+		return nil
+	}
+
+	select {
+	case m.reqCh <- m.tag(lambdaDetailReq{region: item.region, name: item.name}):
+		cmd := m.startLoading()
+		m.activeLambda = item.name
+		m.activeLambdaRegion = item.region
+		m.activeLambdaQualifier = ""
+
+		return cmd
+	default:
+		return nil
+	}
+}
+
 func (m model) viewLambdaDetailUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
-	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
-		m.activeView = viewLambda
-		cmd = nil
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		m.lambdaDetail, cmd = m.lambdaDetail.Update(msg)
+
+		return m, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			// Details of a version or alias are opened from the versions view.
+			m.activeView = viewLambda
+			if m.activeLambdaQualifier != "" {
+				m.activeView = viewVersions
+			}
+
+			// Revealed values are masked again once the details are left.
+			m.envRevealAll = false
+			m.envRevealed = map[string]bool{}
+			m.renderLambdaDetail()
+
+			cmd = nil
+		case key.Matches(msg, m.keys.versions):
+			cmd = m.listVersions()
+		case key.Matches(msg, m.keys.triggers):
+			cmd = m.listTriggers()
+		case key.Matches(msg, m.keys.concurrency):
+			cmd = m.requestConcurrency()
+		case key.Matches(msg, m.keys.editEnv):
+			cmd = m.editEnvVars()
+		case key.Matches(msg, m.keys.next, m.keys.prev):
+			if n := len(m.lambdaDetailInfo.envVars); n > 0 {
+				if key.Matches(msg, m.keys.next) {
+					m.envCursor = (m.envCursor + 1) % n
+				} else {
+					m.envCursor = (m.envCursor + n - 1) % n
+				}
+				m.renderLambdaDetail()
+			}
+		case key.Matches(msg, m.keys.reveal):
+			if m.envCursor < len(m.lambdaDetailInfo.envVars) {
+				key := m.lambdaDetailInfo.envVars[m.envCursor][0]
+				m.envRevealed[key] = !m.envRevealed[key]
+				m.renderLambdaDetail()
+			}
+		case key.Matches(msg, m.keys.revealAll):
+			m.envRevealAll = !m.envRevealAll
+			m.envRevealed = map[string]bool{}
+			m.renderLambdaDetail()
+		}
 	}
 
 	return m, cmd
@@ -226,15 +693,15 @@ func (m model) viewLogStreamUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, m.keys.back):
 			if m.logStreams.FilterValue() == "" {
 				m.activeView = viewLambda
 				m.activeLogGroup = ""
 			}
 			m.logStreams.ResetFilter()
 			cmd = nil
-		case "enter":
+		case key.Matches(msg, m.keys.choose):
 			if !hasSelectedItem {
 				break
 			}
@@ -246,35 +713,136 @@ func (m model) viewLogStreamUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			select {
-			case m.reqCh <- logEventReq{
+			case m.reqCh <- m.tag(logEventReq{
+				region:    m.activeLogRegion,
 				logGroup:  m.activeLogGroup,
 				logStream: selectedItem.name,
-			}:
+			}):
+				cmd = m.startLoading()
 				m.activeLogStream = selectedItem.name
-				m.loading = true
-				cmd = m.spinner.Tick
 			default:
 			}
-		case "r":
+		case key.Matches(msg, m.keys.searchGroup):
+			if m.logStreams.FilterState() == list.Filtering {
+				break
+			}
+
+			cmd = m.openFilter()
+		case key.Matches(msg, m.keys.refresh):
 			select {
-			case m.reqCh <- logStreamReq{logGroup: m.activeLogGroup}:
-				m.loading = true
-				cmd = m.spinner.Tick
+			case m.reqCh <- m.tag(logStreamReq{region: m.activeLogRegion, logGroup: m.activeLogGroup, versions: m.logStreamVersions}):
+				cmd = m.startLoading()
 			default:
 			}
 		}
 	}
 
+	if isPagingInput(msg) && m.activeView == viewLogStream && !m.loading {
+		m.loadMoreLogStreams()
+	}
+
 	return m, cmd
 }
 
 func (m model) viewLogEventUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+
+	if m.logSearching {
+		return m.viewLogSearchUpdate(msg)
+	}
+
+	if m.exporting {
+		return m.viewExportUpdate(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			if m.logSearchInput.Value() != "" {
+				m.logSearchInput.SetValue("")
+				m.updateLogSearch()
+
+				return m, nil
+			}
+
+			m.stopFollow()
+			m.activeView = viewLogStream
+
+			return m, nil
+		case key.Matches(msg, m.keys.follow):
+			return m, m.toggleFollow()
+		case key.Matches(msg, m.keys.formatAll):
+			top := m.topLogEvent()
+			m.logFormat = m.logFormat.next()
+			m.logEventFormats = map[int]logFormat{}
+			m.refreshLogSearchMatches()
+			m.renderLogEvents()
+
+			if top >= 0 && !(m.following && m.followAutoScroll) {
+				m.logEvents.SetYOffset(m.logEventOffsets[top])
+			}
+
+			return m, nil
+		case key.Matches(msg, m.keys.formatTop):
+			top := m.topLogEvent()
+			if top < 0 {
+				return m, nil
+			}
+
+			// The current match moves to another row if the top one no
+			// longer matches, which both need to be rendered again.
+			rows := []int{top}
+			if len(m.logSearchMatches) > 0 {
+				rows = append(rows, m.logSearchMatches[m.logSearchCurrent])
+			}
+
+			m.logEventFormats[top] = m.logEventFormat(top).next()
+			m.refreshLogSearchMatches()
+			if len(m.logSearchMatches) > 0 {
+				rows = append(rows, m.logSearchMatches[m.logSearchCurrent])
+			}
+
+			m.renderLogEventRows(rows...)
+			m.logEvents.SetYOffset(m.logEventOffsets[top])
+
+			return m, nil
+		case key.Matches(msg, m.keys.search):
+			m.logSearching = true
+
+			return m, m.logSearchInput.Focus()
+		case key.Matches(msg, m.keys.export):
+			m.exporting = true
+			m.exportStatus = ""
+			m.exportInput.SetValue(defaultExportPath(m.activeLogStream))
+			m.exportInput.CursorEnd()
+
+			return m, m.exportInput.Focus()
+		case key.Matches(msg, m.keys.invocations):
+			m.showInvocations()
+
+			return m, nil
+		case key.Matches(msg, m.keys.nextMatch, m.keys.prevMatch):
+			if key.Matches(msg, m.keys.nextMatch) {
+				m.moveLogSearchMatch(1)
+			} else {
+				m.moveLogSearchMatch(-1)
+			}
+
+			if m.following {
+				m.followAutoScroll = m.logEvents.AtBottom()
+			}
+
+			return m, nil
+		}
+	}
+
 	m.logEvents, cmd = m.logEvents.Update(msg)
+	if m.following {
+		m.followAutoScroll = m.logEvents.AtBottom()
+	}
 
-	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
-		m.activeView = viewLogStream
-		cmd = nil
+	if isPagingInput(msg) {
+		m.loadMoreLogEvents()
 	}
 
 	return m, cmd
@@ -282,17 +850,22 @@ func (m model) viewLogEventUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) viewLoadingUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		return m, m.cancelLoading()
+	}
+
 	m.spinner, cmd = m.spinner.Update(msg)
 	return m, cmd
 }
 
 func (m model) View() string {
 	if m.err != "" {
-		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.err)
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, errorStyle.Render(m.err))
 	}
 
 	if m.loading {
-		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.spinner.View())
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.loadingView())
 	}
 
 	switch m.activeView {
@@ -303,22 +876,87 @@ func (m model) View() string {
 	case viewLogStream:
 		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.logStreams.View())
 	case viewLogEvent:
-		return m.logEvents.View()
+		return lipgloss.JoinVertical(lipgloss.Left, m.logEvents.View(), m.logEventStatusView())
+	case viewInvoke:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.invokeView())
+	case viewInvokeResult:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.invokeOutput.View())
+	case viewProfile:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.profiles.View())
+	case viewRegion:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.regions.View())
+	case viewInsights:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.insightsView())
+	case viewInsightsResult:
+		return m.insightsOutput.View()
+	case viewVersions:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.versions.View())
+	case viewMetrics:
+		return m.metricsView()
+	case viewAnalytics:
+		return m.analyticsView()
+	case viewFilter:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.filterView())
+	case viewFilterResult:
+		return m.filterResultView()
+	case viewTriggers:
+		return m.triggers.View()
+	case viewInvocations:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.invocations.View())
+	case viewInvocationEvents:
+		return m.invocationEventsView()
+	case viewConcurrency:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.concurrencyView())
+	case viewConcurrencyConfirm:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.concurrencyConfirmView())
+	case viewEnvEdit:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.envEditView())
+	case viewEnvDiff:
+		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.envDiffView())
 	default:
 		return lipgloss.Place(m.winWidth, m.winHeight, lipgloss.Center, lipgloss.Center, m.lambdas.View())
 	}
 }
 
+func (m *model) onRcvLambdaListMsg(msg lambdaListMsg) {
+	m.multiRegion = msg.multiRegion
+	m.lambdas.ResetFilter()
+	m.lambdas.SetItems(msg.items)
+	m.lambdasCachedAt = time.Time{}
+	m.lambdas.Title = m.lambdasTitle()
+	m.activeView = viewLambda
+	m.loading = false
+}
+
 func (m *model) onRcvLambdaDetailMsg(msg lambdaDetailMsg) {
+	m.lambdaDetailInfo = msg.info
+	m.lambdaDetailCachedAt = time.Time{}
+	m.detailRefreshFailed = false
+	m.envRevealAll = false
+	m.envRevealed = map[string]bool{}
+	m.envCursor = 0
+	m.renderLambdaDetail()
+	m.lambdaDetail.GotoTop()
+	m.activeView = viewLambdaDetail
+	m.loading = false
+}
+
+// renderLambdaDetail renders the details of the active function, e.g. after
+// environment variables have been revealed.
+func (m *model) renderLambdaDetail() {
+	info := m.lambdaDetailInfo
 	generalInfoRows := [][]string{
-		{"ARN", msg.info.arn},
-		{"Name", msg.info.name},
-		{"Last Modified", msg.info.lastModified},
-		{"Runtime", msg.info.runtime},
-		{"Architecture", msg.info.arch},
-		{"Memory Size", fmt.Sprintf("%d MB", msg.info.memory)},
-		{"Ephemeral Storage", fmt.Sprintf("%d MB", msg.info.ephemeralStorage)},
-		{"Timeout", fmt.Sprintf("%d seconds", msg.info.timeout)},
+		{"ARN", info.arn},
+		{"Name", info.name},
+		{"Version", info.version},
+		{"Code SHA256", info.codeSha256},
+		{"Last Modified", info.lastModified},
+		{"Runtime", info.runtime},
+		{"Architecture", info.arch},
+		{"Memory Size", fmt.Sprintf("%d MB", info.memory)},
+		{"Ephemeral Storage", fmt.Sprintf("%d MB", info.ephemeralStorage)},
+		{"Timeout", fmt.Sprintf("%d seconds", info.timeout)},
+		{"Reserved Concurrency", formatReservedConcurrency(info.reservedConcurrency)},
 	}
 
 	generalInfo := table.
@@ -328,78 +966,213 @@ func (m *model) onRcvLambdaDetailMsg(msg lambdaDetailMsg) {
 		Width(m.logEvents.Width).
 		Rows(generalInfoRows...)
 
+	envVarRows := make([][]string, 0, len(info.envVars))
+	for _, row := range info.envVars {
+		envVarRows = append(envVarRows, []string{row[0], m.envValue(row[0], row[1])})
+	}
+
 	envVars := table.
 		New().
 		Border(lipgloss.HiddenBorder()).
-		StyleFunc(lambdaDetailTableStyleFunc).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			// Data rows are numbered from one.
+			if row == m.envCursor+1 && col == 0 {
+				return lambdaDetailSelectedFieldNameStyle
+			}
+
+			return lambdaDetailTableStyleFunc(row, col)
+		}).
 		Width(m.logEvents.Width).
-		Rows(msg.info.envVars...)
+		Rows(envVarRows...)
 
 	tags := table.
 		New().
 		Border(lipgloss.HiddenBorder()).
 		StyleFunc(lambdaDetailTableStyleFunc).
 		Width(m.logEvents.Width).
-		Rows(msg.info.tags...)
+		Rows(info.tags...)
 
 	content := lipgloss.JoinVertical(
 		0,
-		lambdaDetailTitleStyle.Render("General"),
+		lambdaDetailTitleStyle.Render(strings.TrimSpace("General "+helpStyle.Render(m.cacheStatus(m.lambdaDetailCachedAt, m.detailRefreshFailed)))),
 		generalInfo.Render(),
 		lambdaDetailTitleStyle.Render("Environment Variables"),
 		envVars.Render(),
 		lambdaDetailTitleStyle.Render("Tags"),
 		tags.Render(),
+		helpLine(
+			keyHelp(m.keys.next, "next variable"),
+			keyHelp(m.keys.prev, "prev variable"),
+			keyHelp(m.keys.reveal, ""),
+			keyHelp(m.keys.revealAll, ""),
+			keyHelp(m.keys.editEnv, ""),
+			keyHelp(m.keys.versions, ""),
+			keyHelp(m.keys.triggers, ""),
+			keyHelp(m.keys.concurrency, ""),
+			keyHelp(m.keys.back, ""),
+		),
 	)
 	m.lambdaDetail.SetContent(content)
 	m.lambdaDetail.Style = m.lambdaDetail.Style.Align(lipgloss.Center)
-	m.activeView = viewLambdaDetail
-	m.loading = false
 }
 
 func (m *model) onRcvLogStreamMsg(msg logStreamMsg) {
-	listItems := make([]list.Item, 0, len(msg.items))
+	m.logStreamsNextToken = msg.nextToken
+	m.loadingMoreLogStreams = false
+	m.logStreams.SetItems(logStreamItems(msg.items))
+	m.logStreams.Title = m.logStreamsTitle()
+	m.activeView = viewLogStream
+	m.loading = false
+}
+
+func (m *model) onRcvLogEventMsg(msg logEventMsg) {
+	m.logEventRows = msg.events
+	m.logEventsForwardToken = msg.forwardToken
+	m.logEventsBackwardToken = msg.backwardToken
+	m.loadingMoreLogEvents = false
+	m.exportStatus = ""
+	m.logSearchInput.SetValue("")
+	m.logSearchExpr = nil
+	m.logSearchErr = nil
+	m.logSearchMatches = nil
+	m.logEventFormats = map[int]logFormat{}
+	m.renderLogEvents()
+	m.logEvents.GotoBottom()
+	m.activeView = viewLogEvent
+	m.loading = false
+}
+
+// renderLogEvents renders all log events into the viewport.
+func (m *model) renderLogEvents() {
+	m.logEventLines = make([]string, len(m.logEventRows))
+	for i := range m.logEventRows {
+		m.logEventLines[i] = m.renderLogEventRow(i)
+	}
+
+	m.setLogEventContent()
+}
+
+// renderLogEventRows renders the given rows again, e.g. after their search
+// highlighting changed.
+func (m *model) renderLogEventRows(rows ...int) {
+	if len(rows) == 0 {
+		return
+	}
+
+	for _, i := range rows {
+		m.logEventLines[i] = m.renderLogEventRow(i)
+	}
+
+	m.setLogEventContent()
+}
+
+// appendLogEvents adds events to the end of the log event viewport.
+func (m *model) appendLogEvents(events [][]string) {
+	start := len(m.logEventRows)
+	m.logEventRows = append(m.logEventRows, events...)
+	m.searchNewLogEvents(start)
+
+	for i := start; i < len(m.logEventRows); i++ {
+		m.logEventLines = append(m.logEventLines, m.renderLogEventRow(i))
+	}
+
+	m.setLogEventContent()
+}
+
+func (m *model) setLogEventContent() {
+	m.logEventOffsets = make([]int, len(m.logEventLines))
 
-	for _, item := range msg.items {
-		itemDescription := fmt.Sprintf("Last Event: %s", item.lastEventTimestamp)
-		if item.expired {
-			itemDescription = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("Expired")
+	offset := 0
+	for i, line := range m.logEventLines {
+		m.logEventOffsets[i] = offset
+		offset += lipgloss.Height(line)
+	}
+
+	m.logEvents.SetContent(strings.Join(m.logEventLines, "\n"))
+}
+
+func (m model) renderLogEventRow(i int) string {
+	row := m.logEventRows[i]
+	timestampStyle := logEventTimestampStyle
+	messageStyle := logEventStyle
+	if i%2 == 1 {
+		timestampStyle = timestampStyle.Background(activeTheme.Stripe)
+		messageStyle = messageStyle.Background(activeTheme.Stripe)
+	}
+
+	// Search highlighting takes precedence over syntax highlighting.
+	format := m.logEventFormat(i)
+	message := m.logEventMessage(i)
+	if m.logSearchExpr != nil && m.logEventMatches(i) {
+		matchStyle := logSearchMatchStyle
+		if m.isCurrentLogSearchMatch(i) {
+			matchStyle = logSearchCurrentMatchStyle
 		}
 
-		listItems = append(listItems, logStreamItem{item.name, itemDescription})
+		message = highlightMatches(message, m.logSearchExpr, matchStyle)
+	} else if format != logFormatRaw {
+		message = formatLogMessage(row[1], format, true)
+	}
+
+	timestamp := timestampStyle.Render(row[0])
+	if width := m.logEvents.Width - lipgloss.Width(timestamp) - logEventStyle.GetHorizontalFrameSize(); width > 0 {
+		messageStyle = messageStyle.Width(width)
 	}
 
-	m.logStreams.SetItems(listItems)
-	m.logStreams.Title = fmt.Sprintf(
-		"Viewing Log Streams - Log Group \"%s\" - Account ID: %s",
-		m.activeLogGroup,
-		m.accountId,
+	height := max(lipgloss.Height(timestamp), lipgloss.Height(messageStyle.Render(message)))
+
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		timestampStyle.Height(height).Render(row[0]),
+		messageStyle.Height(height).Render(message),
 	)
-	m.activeView = viewLogStream
-	m.loading = false
 }
 
-func (m *model) onRcvLogEventMsg(msg logEventMsg) {
-	t := table.
-		New().
-		Border(lipgloss.HiddenBorder()).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			style := logEventStyle
-			if col == 0 {
-				style = logEventTimestampStyle
-			}
+// logEventFormat returns the format the given log event is rendered in.
+func (m model) logEventFormat(row int) logFormat {
+	if format, ok := m.logEventFormats[row]; ok {
+		return format
+	}
 
-			if row%2 == 1 {
-				style = style.Background(lipgloss.Color("236"))
-			}
-			return style
-		}).
-		Width(m.logEvents.Width).
-		Rows(msg.events...)
+	return m.logFormat
+}
 
-	m.logEvents.SetContent(t.Render())
-	m.activeView = viewLogEvent
-	m.loading = false
+// topLogEvent returns the index of the log event shown at the top of the
+// viewport, or -1 if there are no events.
+func (m model) topLogEvent() int {
+	return sort.SearchInts(m.logEventOffsets, m.logEvents.YOffset+1) - 1
+}
+
+func (m model) logEventStatusView() string {
+	help := helpLine(
+		keyHelp(m.keys.search, ""),
+		keyHelp(m.keys.nextMatch, ""),
+		keyHelp(m.keys.prevMatch, ""),
+		keyHelp(m.keys.follow, ""),
+		keyHelp(m.keys.formatAll, ""),
+		keyHelp(m.keys.formatTop, ""),
+		keyHelp(m.keys.invocations, ""),
+		keyHelp(m.keys.export, ""),
+		keyHelp(m.keys.back, ""),
+	)
+	switch {
+	case m.logSearching:
+		help = m.logSearchPromptView()
+	case m.exporting:
+		help = m.exportPromptView()
+	}
+
+	format := ""
+	if m.logFormat != logFormatRaw {
+		format = "format: " + m.logFormat.String()
+	}
+
+	status := strings.Join(slices.DeleteFunc([]string{m.exportStatus, m.logEventPagingStatusView(), m.logSearchStatusView(), format, m.followStatusView()}, func(s string) bool {
+		return s == ""
+	}), " • ")
+	gap := m.winWidth - lipgloss.Width(help) - lipgloss.Width(status) - logEventStatusStyle.GetHorizontalFrameSize()
+
+	return logEventStatusStyle.Render(help + strings.Repeat(" ", max(gap, 1)) + status)
 }
 
 func lambdaDetailTableStyleFunc(row, col int) lipgloss.Style {
@@ -407,11 +1180,11 @@ func lambdaDetailTableStyleFunc(row, col int) lipgloss.Style {
 
 	switch {
 	case col == 0 && row%2 == 0:
-		style = lambdaDetailFieldNameStyle.Background(lipgloss.Color("#20355c"))
+		style = lambdaDetailFieldNameStyle.Background(activeTheme.HeaderStripe)
 	case col == 0 && row%2 == 1:
 		style = lambdaDetailFieldNameStyle
 	case col == 1 && row%2 == 0:
-		style = lambdaDetailFieldValueStyle.Background(lipgloss.Color("236"))
+		style = lambdaDetailFieldValueStyle.Background(activeTheme.Stripe)
 	case col == 1 && row%2 == 1:
 		style = lambdaDetailFieldValueStyle
 	}
@@ -419,26 +1192,126 @@ func lambdaDetailTableStyleFunc(row, col int) lipgloss.Style {
 	return style
 }
 
-func newModel(reqCh chan interface{}, accountId string, lambdas []list.Item) model {
+func newModel(reqCh chan interface{}, canceler *requestCanceler, sess session, regions []string, multiRegion bool, lambdas []list.Item) model {
 	model := model{
-		accountId:    accountId,
-		reqCh:        reqCh,
-		lambdas:      list.New(lambdas, list.NewDefaultDelegate(), 0, 0),
-		logStreams:   list.New(nil, list.NewDefaultDelegate(), 0, 0),
-		logEvents:    viewport.New(0, 0),
-		lambdaDetail: viewport.New(0, 0),
-		spinner:      spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(spinnerStyle)),
-		activeView:   viewLambda,
-	}
-	model.lambdas.Title = fmt.Sprintf("Viewing Lambdas - Account ID: %s", accountId)
-	model.lambdas.KeyMap.NextPage = key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "next page"))
-	model.lambdas.KeyMap.PrevPage = key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "prev page"))
-	model.lambdas.AdditionalShortHelpKeys = func() []key.Binding {
+		canceler:         canceler,
+		listRegions:      regions,
+		multiRegion:      multiRegion,
+		accountId:        sess.accountId,
+		profile:          sess.profile,
+		region:           sess.region,
+		reqCh:            reqCh,
+		lambdas:          list.New(lambdas, list.NewDefaultDelegate(), 0, 0),
+		logStreams:       list.New(nil, list.NewDefaultDelegate(), 0, 0),
+		versions:         list.New(nil, list.NewDefaultDelegate(), 0, 0),
+		invocations:      list.New(nil, list.NewDefaultDelegate(), 0, 0),
+		invocationEvents: viewport.New(0, 0),
+		profiles:         list.New(nil, list.NewDefaultDelegate(), 0, 0),
+		regions:          newRegionList(),
+		logEvents:        viewport.New(0, 0),
+		lambdaDetail:     viewport.New(0, 0),
+		triggers:         viewport.New(0, 0),
+		logSearchInput:   newLogSearchInput(),
+		exportInput:      newExportInput(),
+		concurrencyInput: newConcurrencyInput(),
+		filterInputs:     newFilterInputs(),
+		filterOutput:     viewport.New(0, 0),
+		logEventFormats:  map[int]logFormat{},
+		invokePayload:    newInvokePayload(),
+		envEditor:        newEnvEditor(),
+		envMasker:        envMasker{mode: maskSecrets, patterns: defaultSecretPatterns},
+		envRevealed:      map[string]bool{},
+		invokeOutput:     viewport.New(0, 0),
+		insightsQuery:    newInsightsQuery(),
+		insightsOutput:   viewport.New(0, 0),
+		insightsRange:    2,
+		spinner:          spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(spinnerStyle)),
+		activeView:       viewLambda,
+	}
+	model.logEvents.KeyMap = logEventsKeyMap()
+	model.lambdas.Title = fmt.Sprintf("Viewing Lambdas - %s", model.sessionTitle())
+	model.profiles.Title = "Select Profile"
+	model.setKeyMap(defaultKeyMap())
+	model.setTheme(darkTheme)
+
+	return model
+}
+
+// setKeyMap sets the key bindings and the help of the lists showing them.
+func (m *model) setKeyMap(keys keyMap) {
+	m.keys = keys
+
+	// Lists quit on their own unless filtering.
+	listKeys := listKeyMap()
+	for _, l := range []*list.Model{&m.lambdas, &m.logStreams, &m.versions, &m.invocations, &m.profiles, &m.regions} {
+		l.KeyMap.PrevPage = listKeys.PrevPage
+		l.KeyMap.NextPage = listKeys.NextPage
+		l.KeyMap.Quit = keys.quit
+		l.KeyMap.ForceQuit = keys.forceQuit
+	}
+
+	m.lambdas.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
-			key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "invoke")),
-			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "details")),
+			keys.invoke,
+			describe(keys.choose, "details"),
+			keys.logStreams,
+			keys.profile,
+			keys.multiRegion,
+			keys.insights,
+			keys.metrics,
+			keys.analytics,
+			keys.refreshAll,
 		}
 	}
+	m.lambdas.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.mouse}
+	}
+	m.logStreams.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.searchGroup, keys.refresh}
+	}
+	m.versions.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{describe(keys.choose, "details"), keys.logStreams}
+	}
+	m.invocations.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{describe(keys.choose, "log events")}
+	}
+}
 
-	return model
+// sessionTitle describes the active account, profile and region for list
+// titles.
+func (m model) sessionTitle() string {
+	if m.multiRegion {
+		return fmt.Sprintf("Account ID: %s - Profile: %s - Regions: %s", m.accountId, m.profile, strings.Join(m.listRegions, ", "))
+	}
+
+	return fmt.Sprintf("Account ID: %s - Profile: %s - Region: %s", m.accountId, m.profile, m.region)
+}
+
+// lambdaListRegions returns the regions to list functions from, which is
+// empty unless multi-region mode is enabled.
+func (m model) lambdaListRegions() []string {
+	if !m.multiRegion {
+		return nil
+	}
+
+	return m.listRegions
+}
+
+// capturesInput reports whether the active view is a text input or a list
+// being filtered, in which case single character keys must not trigger global
+// actions.
+func (m model) capturesInput() bool {
+	switch m.activeView {
+	case viewInvoke, viewInsights, viewEnvEdit, viewFilter:
+		return true
+	case viewLogEvent:
+		return m.logSearching || m.exporting
+	case viewConcurrency:
+		return m.concurrencyPrompt != ""
+	}
+
+	// Typed keys filter the items of a list.
+	l, _ := m.activeList()
+
+	return l != nil && l.FilterState() == list.Filtering
 }
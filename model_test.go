@@ -0,0 +1,1447 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testHarness drives a model the way the Bubble Tea program does, answering
+// requests synchronously through handleRequest.
+type testHarness struct {
+	t     *testing.T
+	sess  session
+	reqCh chan interface{}
+	model model
+}
+
+func newTestHarness(t *testing.T, backend *fakeBackend) *testHarness {
+	t.Helper()
+
+	sess := backend.session()
+	items, err := listLambdaFunctions(context.Background(), sess, nil)
+	if err != nil {
+		t.Fatalf("listing functions: %v", err)
+	}
+
+	// A buffered channel lets the non-blocking sends of the model succeed
+	// without a concurrently running request handler.
+	reqCh := make(chan interface{}, 1)
+	h := &testHarness{
+		t:     t,
+		sess:  sess,
+		reqCh: reqCh,
+		model: newModel(reqCh, nil, sess, nil, false, items),
+	}
+	h.send(tea.WindowSizeMsg{Width: 160, Height: 50})
+
+	return h
+}
+
+// send passes msg to the model and answers all resulting requests.
+func (h *testHarness) send(msg tea.Msg) {
+	h.t.Helper()
+
+	for msg != nil {
+		next, _ := h.model.Update(msg)
+		h.model = next.(model)
+
+		select {
+		case req := <-h.reqCh:
+			id, req := untagRequest(req)
+			msg = tagResponse(id, handleRequest(context.Background(), &h.sess, req))
+		default:
+			msg = nil
+		}
+	}
+}
+
+func (h *testHarness) press(keys ...string) {
+	h.t.Helper()
+
+	for _, k := range keys {
+		h.send(keyMsg(k))
+	}
+}
+
+// keyMsg returns the message of the named key like "enter" or "ctrl+s", or of
+// typing k.
+func keyMsg(k string) tea.KeyMsg {
+	return bindingMsg(key.NewBinding(key.WithKeys(k)))
+}
+
+func (h *testHarness) assertView(activeView string) {
+	h.t.Helper()
+
+	if h.model.activeView != activeView {
+		h.t.Fatalf("active view is %q, want %q", h.model.activeView, activeView)
+	}
+
+	if h.model.err != "" {
+		h.t.Fatalf("unexpected error: %s", h.model.err)
+	}
+}
+
+func (h *testHarness) assertContains(substrings ...string) {
+	h.t.Helper()
+
+	view := h.model.View()
+	for _, s := range substrings {
+		if !strings.Contains(view, s) {
+			h.t.Fatalf("view does not contain %q:\n%s", s, view)
+		}
+	}
+}
+
+func (h *testHarness) assertNotContains(substrings ...string) {
+	h.t.Helper()
+
+	view := h.model.View()
+	for _, s := range substrings {
+		if strings.Contains(view, s) {
+			h.t.Fatalf("view contains %q:\n%s", s, view)
+		}
+	}
+}
+
+func newScriptedBackend() *fakeBackend {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{
+		name:    "orders-api",
+		runtime: lambdatypes.RuntimeNodejs20x,
+		memory:  512,
+		timeout: 30,
+		env:     map[string]string{"TABLE_NAME": "orders"},
+		tags:    map[string]string{"team": "checkout"},
+	})
+	backend.addFunction(fakeFunction{
+		name:    "payments-worker",
+		runtime: lambdatypes.RuntimeProvidedal2023,
+		memory:  1024,
+		timeout: 900,
+		env:     map[string]string{"QUEUE_URL": "https://sqs.example/payments"},
+	})
+
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/09/01/[$LATEST]aaaa",
+		events: []fakeEvent{
+			{timestamp: 1725192000000, message: "START RequestId: 1"},
+			{timestamp: 1725192001000, message: "order created"},
+			{timestamp: 1725192002000, message: "END RequestId: 1"},
+		},
+	})
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/08/31/[$LATEST]bbbb",
+		events: []fakeEvent{
+			{timestamp: 1725105600000, message: "older stream"},
+		},
+	})
+	backend.addStream(fakeLogGroup("payments-worker"), fakeStream{
+		name:   "2024/09/01/[$LATEST]cccc",
+		events: []fakeEvent{{timestamp: 1725192000000, message: "payment settled"}},
+	})
+
+	return backend
+}
+
+func TestLambdaList(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	h.assertView(viewLambda)
+	h.assertContains("orders-api", "payments-worker", "/aws/lambda/orders-api", "Account ID: 123456789012")
+}
+
+func TestLambdaListPagination(t *testing.T) {
+	backend := newScriptedBackend()
+	backend.pageSize = 1
+
+	h := newTestHarness(t, backend)
+
+	if got := len(h.model.lambdas.Items()); got != 2 {
+		t.Fatalf("got %d functions, want 2", got)
+	}
+
+	if backend.calls["ListFunctions"] != 2 {
+		t.Fatalf("got %d ListFunctions calls, want 2", backend.calls["ListFunctions"])
+	}
+}
+
+func TestLambdaDetail(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("down", "enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains(
+		"arn:aws:lambda:eu-central-1:123456789012:function:payments-worker",
+		"provided.al2023",
+		"1024 MB",
+		"900 seconds",
+		"QUEUE_URL",
+	)
+
+	h.press("esc")
+	h.assertView(viewLambda)
+
+	// Reopening the same function uses the already fetched details.
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+
+	if backend.calls["GetFunction"] != 1 {
+		t.Fatalf("got %d GetFunction calls, want 1", backend.calls["GetFunction"])
+	}
+}
+
+func TestLogStreamsAndEvents(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	h.press("l")
+	h.assertView(viewLogStream)
+	h.assertContains("Log Group \"/aws/lambda/orders-api\"", "2024/09/01/[$LATEST]aaaa", "2024/08/31/[$LATEST]bbbb")
+
+	if h.model.activeLogGroup != "/aws/lambda/orders-api" {
+		t.Fatalf("active log group is %q", h.model.activeLogGroup)
+	}
+
+	h.press("enter")
+	h.assertView(viewLogEvent)
+	h.assertContains("START RequestId: 1", "order created", "END RequestId: 1")
+	h.assertNotContains("older stream")
+
+	view := h.model.View()
+	if strings.Index(view, "START RequestId: 1") > strings.Index(view, "END RequestId: 1") {
+		t.Fatalf("events are not in chronological order:\n%s", view)
+	}
+
+	h.press("esc")
+	h.assertView(viewLogStream)
+
+	h.press("down", "enter")
+	h.assertView(viewLogEvent)
+	h.assertContains("older stream")
+	h.assertNotContains("order created")
+
+	h.press("esc", "esc")
+	h.assertView(viewLambda)
+}
+
+func TestCancelLoading(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	// Leave the request unanswered, as if it were still in flight.
+	next, _ := h.model.Update(keyMsg("l"))
+	h.model = next.(model)
+	id, req := untagRequest(<-h.reqCh)
+
+	if !h.model.loading {
+		t.Fatal("model is not loading")
+	}
+	h.assertContains("Loading...", "Press esc to cancel")
+
+	h.press("esc")
+	h.assertView(viewLambda)
+
+	if h.model.loading || h.model.activeLogGroup != "" {
+		t.Fatalf("loading state was not reset: loading %t, log group %q", h.model.loading, h.model.activeLogGroup)
+	}
+
+	// A response completed just before cancelling is dropped.
+	h.send(tagResponse(id, handleRequest(context.Background(), &h.sess, req)))
+	h.assertView(viewLambda)
+
+	h.press("l")
+	h.assertView(viewLogStream)
+}
+
+// newPagedBackend returns a backend serving a single function with five log
+// streams of five events each, in pages of two.
+func newPagedBackend() *fakeBackend {
+	backend := newFakeBackend("eu-central-1")
+	backend.pageSize = 2
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+
+	for i := range 5 {
+		stream := fakeStream{name: fmt.Sprintf("stream-%d", i)}
+		for j := range 5 {
+			stream.events = append(stream.events, fakeEvent{
+				timestamp: int64(1725192000000 + i*60000 + j*1000),
+				message:   fmt.Sprintf("event %d", j),
+			})
+		}
+
+		backend.addStream(fakeLogGroup("orders-api"), stream)
+	}
+
+	return backend
+}
+
+func TestLogStreamPagination(t *testing.T) {
+	backend := newPagedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("l")
+	h.assertView(viewLogStream)
+	h.assertContains("stream-4", "stream-3", "more available")
+
+	if n := len(h.model.logStreams.Items()); n != 2 {
+		t.Fatalf("got %d log streams, want 2", n)
+	}
+
+	// Moving to the last stream loads the next page.
+	h.press("down")
+	if n := len(h.model.logStreams.Items()); n != 4 {
+		t.Fatalf("got %d log streams, want 4", n)
+	}
+
+	h.press("down", "down")
+	if n := len(h.model.logStreams.Items()); n != 5 {
+		t.Fatalf("got %d log streams, want 5", n)
+	}
+	h.assertContains("stream-0")
+	h.assertNotContains("more available")
+
+	h.press("up", "down")
+	if backend.calls["DescribeLogStreams"] != 3 {
+		t.Fatalf("got %d DescribeLogStreams calls, want 3", backend.calls["DescribeLogStreams"])
+	}
+}
+
+func TestLogEventPagination(t *testing.T) {
+	h := newTestHarness(t, newPagedBackend())
+
+	h.press("l", "enter")
+	h.assertView(viewLogEvent)
+	h.assertContains("event 3", "event 4", "older events available")
+	h.assertNotContains("event 2")
+
+	h.press("/")
+	for _, r := range "event 4" {
+		h.press(string(r))
+	}
+	h.press("enter")
+	h.assertContains("match 1/1")
+
+	// Scrolling up at the top of the viewport loads older events.
+	h.press("up")
+	h.assertContains("event 1", "event 4", "match 1/1")
+	h.assertNotContains("event 0")
+
+	h.press("up", "up")
+	h.assertContains("event 0")
+	h.assertNotContains("older events available")
+
+	view := h.model.View()
+	if strings.Index(view, "event 0") > strings.Index(view, "event 4") {
+		t.Fatalf("events are not in chronological order:\n%s", view)
+	}
+
+	if want := []int{4}; !slices.Equal(h.model.logSearchMatches, want) {
+		t.Fatalf("got matches %v, want %v", h.model.logSearchMatches, want)
+	}
+}
+
+func TestVersionsAndAliases(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{
+		name:     "orders-api",
+		runtime:  lambdatypes.RuntimeNodejs20x,
+		versions: []string{"1", "2", "3"},
+		aliases:  []fakeAlias{{name: "prod", version: "3", weights: map[string]float64{"2": 0.1}}},
+	})
+	for _, version := range []string{"3", "2", "$LATEST"} {
+		backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+			name:   fmt.Sprintf("2024/09/01/[%s]%s", version, strings.Repeat("a", 4)),
+			events: []fakeEvent{{timestamp: 1725192000000, message: "version " + version}},
+		})
+	}
+	h := newTestHarness(t, backend)
+
+	h.press("enter", "v")
+	h.assertView(viewVersions)
+	h.assertContains("alias prod → 3 (90%), 2 (10%)", "version $LATEST", "version 1", "sha-3")
+
+	// Details of the alias resolve to its primary version.
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains("function:orders-api:prod", "sha-3")
+
+	h.press("esc")
+	h.assertView(viewVersions)
+
+	// Leaving the versions returns to the details of the function.
+	h.press("esc")
+	h.assertView(viewLambdaDetail)
+	h.assertNotContains("function:orders-api:prod")
+
+	h.press("v")
+	h.assertView(viewVersions)
+
+	h.press("l")
+	h.assertView(viewLogStream)
+	h.assertContains("(alias prod", "[3]aaaa", "[2]aaaa")
+	h.assertNotContains("[$LATEST]aaaa")
+
+	// Listing the streams from the function list removes the filter.
+	h.press("esc", "l")
+	h.assertView(viewLogStream)
+	h.assertContains("[$LATEST]aaaa")
+	h.assertNotContains("(alias prod")
+}
+
+func TestMetrics(t *testing.T) {
+	backend := newScriptedBackend()
+	backend.metrics["Invocations"] = []float64{4, 0, 8, 2}
+	backend.metrics["Duration"] = []float64{120.5, 80.25}
+	backend.metrics["Duration p99"] = []float64{950}
+	h := newTestHarness(t, backend)
+
+	h.press("M")
+	h.assertView(viewMetrics)
+	h.assertContains(
+		"Metrics - Function \"orders-api\"",
+		"Invocations", "Errors", "Duration", "Throttles", "ConcurrentExecutions",
+		"p99 8",
+		// Only the two periods with data are summarised.
+		"min 80.25 ms • avg 100.38 ms • p99 950 ms",
+	)
+
+	h.press("r", "tab")
+	h.assertView(viewMetrics)
+
+	if backend.calls["GetMetricData"] != 3 {
+		t.Fatalf("got %d GetMetricData calls, want 3", backend.calls["GetMetricData"])
+	}
+
+	if label := metricsRanges[h.model.metricsRange].label; label != "3h" {
+		t.Fatalf("selected range is %s, want 3h", label)
+	}
+
+	h.press("esc")
+	h.assertView(viewLambda)
+}
+
+func TestEditEnvVars(t *testing.T) {
+	pollInterval := functionUpdatePollInterval
+	functionUpdatePollInterval = time.Millisecond
+	t.Cleanup(func() { functionUpdatePollInterval = pollInterval })
+
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("enter", "E")
+	h.assertView(viewEnvEdit)
+
+	if got := h.model.envEditor.Value(); got != "TABLE_NAME=orders" {
+		t.Fatalf("editor holds %q", got)
+	}
+
+	// Typing "q" into the editor must not quit.
+	h.press("q")
+	h.assertView(viewEnvEdit)
+
+	h.model.envEditor.SetValue("TABLE_NAME=orders-v2\nSTAGE=prod")
+	h.press("ctrl+s")
+	h.assertView(viewEnvDiff)
+	h.assertContains("~ TABLE_NAME=orders → orders-v2", "+ STAGE=prod")
+
+	h.press("esc")
+	h.assertView(viewEnvEdit)
+
+	h.press("ctrl+s", "enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains("orders-v2", "STAGE")
+
+	if backend.calls["UpdateFunctionConfiguration"] != 1 {
+		t.Fatalf("got %d UpdateFunctionConfiguration calls, want 1", backend.calls["UpdateFunctionConfiguration"])
+	}
+
+	h.press("E")
+	h.model.envEditor.SetValue("not a variable")
+	h.press("ctrl+s")
+	if !strings.Contains(h.model.err, "expected KEY=value") {
+		t.Fatalf("got error %q", h.model.err)
+	}
+
+	// Changes made to an outdated configuration are rejected.
+	h.press("enter")
+	backend.functions[0].revision++
+	h.model.envEditor.SetValue("TABLE_NAME=orders-v3")
+	h.press("ctrl+s", "enter")
+	if !strings.Contains(h.model.err, "changed since its details were loaded") {
+		t.Fatalf("got error %q", h.model.err)
+	}
+
+	if backend.functions[0].env["TABLE_NAME"] != "orders-v2" {
+		t.Fatalf("outdated update was applied: %v", backend.functions[0].env)
+	}
+}
+
+func TestMaskEnvVars(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{
+		name: "orders-api",
+		env:  map[string]string{"DB_PASSWORD": "hunter2", "TABLE_NAME": "orders", "API_TOKEN": "t0ken"},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("enter")
+	h.assertContains("orders", maskedValue)
+	h.assertNotContains("hunter2", "t0ken")
+
+	// Variables are sorted by key, so the second one is DB_PASSWORD.
+	h.press("tab", "x")
+	h.assertContains("hunter2")
+	h.assertNotContains("t0ken")
+
+	h.press("x")
+	h.assertNotContains("hunter2")
+
+	h.press("X")
+	h.assertContains("hunter2", "t0ken")
+
+	// Reopening the details masks the values again.
+	h.press("esc", "enter")
+	h.assertNotContains("hunter2", "t0ken")
+}
+
+func TestEditMaskedEnvVars(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{
+		name: "orders-api",
+		env:  map[string]string{"DB_PASSWORD": "hunter2", "TABLE_NAME": "orders", "API_TOKEN": "t0ken"},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("enter", "E")
+	h.assertView(viewEnvEdit)
+	h.assertNotContains("hunter2", "t0ken")
+
+	if got, want := h.model.envEditor.Value(), "API_TOKEN="+maskedValue+"\nDB_PASSWORD="+maskedValue+"\nTABLE_NAME=orders"; got != want {
+		t.Fatalf("editor holds %q, want %q", got, want)
+	}
+
+	// Masked values left in place keep their value.
+	h.model.envEditor.SetValue("API_TOKEN=n3w\nDB_PASSWORD=" + maskedValue + "\nTABLE_NAME=orders-v2")
+	h.press("ctrl+s")
+	h.assertView(viewEnvDiff)
+
+	if want := map[string]string{"API_TOKEN": "n3w", "DB_PASSWORD": "hunter2", "TABLE_NAME": "orders-v2"}; !maps.Equal(h.model.envVars, want) {
+		t.Fatalf("got variables %v, want %v", h.model.envVars, want)
+	}
+
+	if len(h.model.envChanges) != 2 {
+		t.Fatalf("got changes %+v, want API_TOKEN and TABLE_NAME", h.model.envChanges)
+	}
+
+	h.assertNotContains("t0ken")
+	h.press("X")
+	h.assertContains("t0ken → n3w")
+
+	// Only unedited masked values are revealed.
+	h.press("esc", "ctrl+x")
+	if got, want := h.model.envEditor.Value(), "API_TOKEN=n3w\nDB_PASSWORD=hunter2\nTABLE_NAME=orders-v2"; got != want {
+		t.Fatalf("editor holds %q, want %q", got, want)
+	}
+
+	h.press("ctrl+x")
+	h.assertNotContains("hunter2")
+
+	// Values revealed in the diff stay masked in the details.
+	h.press("esc")
+	h.assertView(viewLambdaDetail)
+	h.assertNotContains("hunter2", "t0ken")
+}
+
+func TestFollowAppendsNewEvents(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("l", "enter", "f")
+	h.assertView(viewLogEvent)
+
+	if !h.model.following {
+		t.Fatal("follow mode is not enabled")
+	}
+
+	backend.appendEvents(fakeLogGroup("orders-api"), "2024/09/01/[$LATEST]aaaa", fakeEvent{
+		timestamp: 1725192003000,
+		message:   "late event",
+	})
+	h.send(followTickMsg{id: h.model.followId})
+	h.assertContains("late event", "following")
+
+	h.press("f")
+	if h.model.following {
+		t.Fatal("follow mode is still enabled")
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("i")
+	h.assertView(viewInvoke)
+	h.assertContains("Invoke \"orders-api\"")
+
+	h.model.invokePayload.SetValue(`{"orderId": 42}`)
+	h.press("ctrl+s")
+	h.assertView(viewInvokeResult)
+	h.assertContains("200", "$LATEST", `"orderId": 42`, "END RequestId: fake")
+
+	h.press("esc")
+	h.assertView(viewInvoke)
+}
+
+func TestInvokeRejectsInvalidPayload(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	h.press("i")
+	h.model.invokePayload.SetValue(`{"orderId": `)
+	h.press("ctrl+s")
+
+	if !strings.Contains(h.model.err, "not valid JSON") {
+		t.Fatalf("got error %q", h.model.err)
+	}
+
+	if backend.calls["Invoke"] != 0 {
+		t.Fatal("invalid payload was sent")
+	}
+}
+
+func TestErrorIsDismissed(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+
+	backend.functions = backend.functions[1:]
+	h.press("enter")
+
+	if !strings.Contains(h.model.err, "function not found") {
+		t.Fatalf("got error %q", h.model.err)
+	}
+	h.assertContains("function not found")
+
+	h.press("esc")
+	h.assertView(viewLambda)
+}
+
+func TestReuseRegionClients(t *testing.T) {
+	created := map[string]int{}
+	forRegion := reuseRegionClients(func(region string) regionClients {
+		created[region]++
+
+		return newFakeBackend(region).clients()
+	})
+
+	for _, region := range []string{"eu-west-1", "us-east-1", "eu-west-1", "eu-west-1"} {
+		forRegion(region)
+	}
+
+	if want := map[string]int{"eu-west-1": 1, "us-east-1": 1}; !maps.Equal(created, want) {
+		t.Fatalf("created clients %v, want %v", created, want)
+	}
+}
+
+func TestMultiRegionList(t *testing.T) {
+	backend := newScriptedBackend()
+	west := newFakeBackend("eu-west-1")
+	west.addFunction(fakeFunction{name: "edge-router", runtime: lambdatypes.RuntimePython312, memory: 128})
+
+	sess := backend.session()
+	sess.forRegion = func(region string) regionClients {
+		return west.clients()
+	}
+
+	items, err := listLambdaFunctions(context.Background(), sess, []string{"eu-central-1", "eu-west-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []lambdaItem{
+		{name: "edge-router", logGroup: "/aws/lambda/edge-router", region: "eu-west-1"},
+		{name: "orders-api", logGroup: "/aws/lambda/orders-api", region: "eu-central-1"},
+		{name: "payments-worker", logGroup: "/aws/lambda/payments-worker", region: "eu-central-1"},
+	}
+
+	if len(items) != len(want) {
+		t.Fatalf("got %d functions, want %d", len(items), len(want))
+	}
+
+	for i, item := range items {
+		if item.(lambdaItem) != want[i] {
+			t.Errorf("item %d is %+v, want %+v", i, item, want[i])
+		}
+	}
+
+	reqCh := make(chan interface{}, 1)
+	h := &testHarness{t: t, sess: sess, reqCh: reqCh, model: newModel(reqCh, nil, sess, []string{"eu-central-1", "eu-west-1"}, true, items)}
+	h.send(tea.WindowSizeMsg{Width: 160, Height: 50})
+	h.assertContains("Regions: eu-central-1, eu-west-1", "eu-west-1 - /aws/lambda/edge-router")
+
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains("arn:aws:lambda:eu-west-1:123456789012:function:edge-router")
+
+	if west.calls["GetFunction"] != 1 || backend.calls["GetFunction"] != 0 {
+		t.Fatal("details were not requested from the regional client")
+	}
+}
+
+func TestInsightsQuery(t *testing.T) {
+	pollInterval := queryPollInterval
+	queryPollInterval = time.Millisecond
+	t.Cleanup(func() { queryPollInterval = pollInterval })
+
+	backend := newScriptedBackend()
+	backend.queryPolls = 2
+	h := newTestHarness(t, backend)
+
+	h.press("I")
+	h.assertView(viewInsights)
+	h.assertContains("Logs Insights - Log Group \"/aws/lambda/orders-api\"", "fields @timestamp, @message")
+
+	h.send(tea.KeyMsg{Type: tea.KeyTab})
+	if label := insightsRanges[h.model.insightsRange].label; label != "3h" {
+		t.Fatalf("selected range is %s, want 3h", label)
+	}
+
+	h.press("ctrl+s")
+	h.assertView(viewInsightsResult)
+	h.assertContains("@timestamp", "@logStream", "@message", "order created", "older stream", "4 rows", "last 3h")
+	h.assertNotContains("@ptr", "opaque")
+
+	if backend.calls["GetQueryResults"] != 3 {
+		t.Fatalf("got %d GetQueryResults calls, want 3", backend.calls["GetQueryResults"])
+	}
+
+	h.press("esc")
+	h.assertView(viewInsights)
+}
+
+func TestLogSearch(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	h.press("l", "enter", "/", "r", "e", "q")
+	if !h.model.logSearching {
+		t.Fatal("search prompt is not open")
+	}
+
+	// Typing "q" into the prompt must not quit.
+	h.assertView(viewLogEvent)
+
+	if want := []int{0, 2}; !slices.Equal(h.model.logSearchMatches, want) {
+		t.Fatalf("got matches %v, want %v", h.model.logSearchMatches, want)
+	}
+
+	h.press("enter")
+	h.assertContains("match 1/2")
+
+	h.press("n")
+	h.assertContains("match 2/2")
+	h.press("n")
+	h.assertContains("match 1/2")
+	h.press("N")
+	h.assertContains("match 2/2")
+
+	// Regex search: "^(START|END) " only matches the markers.
+	h.press("/")
+	h.send(tea.KeyMsg{Type: tea.KeyCtrlR})
+	for range "req" {
+		h.send(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	for _, r := range "^(order|END) " {
+		h.press(string(r))
+	}
+	h.press("enter")
+
+	if want := []int{1, 2}; !slices.Equal(h.model.logSearchMatches, want) {
+		t.Fatalf("got regex matches %v, want %v", h.model.logSearchMatches, want)
+	}
+
+	h.press("/", "(")
+	h.assertContains("invalid regex")
+	h.press("esc")
+
+	if h.model.logSearchExpr != nil || len(h.model.logSearchMatches) != 0 {
+		t.Fatal("search was not cleared")
+	}
+
+	h.assertView(viewLogEvent)
+	h.press("esc")
+	h.assertView(viewLogStream)
+}
+
+func TestLogSearchFormatted(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "stream",
+		events: []fakeEvent{
+			{timestamp: 1, message: `{"order": 1, "status": "created"}`},
+			{timestamp: 2, message: `{"order":2,"status":"created"}`},
+		},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("l", "enter", "/")
+	for _, r := range `":"created` {
+		h.press(string(r))
+	}
+	h.press("enter")
+
+	// The raw message of the first event has a space after the colon.
+	if want := []int{1}; !slices.Equal(h.model.logSearchMatches, want) {
+		t.Fatalf("got raw matches %v, want %v", h.model.logSearchMatches, want)
+	}
+
+	h.press("J")
+	if want := []int{0, 1}; !slices.Equal(h.model.logSearchMatches, want) {
+		t.Fatalf("got compact matches %v, want %v", h.model.logSearchMatches, want)
+	}
+	h.assertContains("match 2/2")
+
+	// Expanded events have a space after every key.
+	h.press("J")
+	if len(h.model.logSearchMatches) != 0 {
+		t.Fatalf("got expanded matches %v, want none", h.model.logSearchMatches)
+	}
+}
+
+func TestCompileLogSearch(t *testing.T) {
+	tests := []struct {
+		query   string
+		isRegex bool
+		subject string
+		want    bool
+	}{
+		{"error", false, "ERROR: timeout", true},
+		{"Error", false, "ERROR: timeout", false},
+		{"a.b", false, "axb", false},
+		{"a.b", true, "axb", true},
+		{`\d{3}`, true, "status 503", true},
+	}
+
+	for _, tt := range tests {
+		re, err := compileLogSearch(tt.query, tt.isRegex)
+		if err != nil {
+			t.Fatalf("compiling %q: %v", tt.query, err)
+		}
+
+		if got := re.MatchString(tt.subject); got != tt.want {
+			t.Errorf("%q (regex %v) matching %q = %v, want %v", tt.query, tt.isRegex, tt.subject, got, tt.want)
+		}
+	}
+}
+
+func TestLogEventFormats(t *testing.T) {
+	backend := newScriptedBackend()
+	backend.appendEvents(fakeLogGroup("payments-worker"), "2024/09/01/[$LATEST]cccc", fakeEvent{
+		timestamp: 1725192001000,
+		message:   `{"level":"INFO","message":"payment captured","amount":42}`,
+	}, fakeEvent{
+		timestamp: 1725192002000,
+		message:   `{"level":"WARN","message":"payment retried","attempt":2}`,
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("down", "l", "enter")
+	h.assertContains(`{"level":"INFO","message":"payment captured","amount":42}`)
+
+	h.press("J")
+	h.assertContains(`INFO payment captured {"amount":42}`, `WARN payment retried {"attempt":2}`, "format: compact")
+
+	h.press("J")
+	h.assertContains(`"amount": 42`, "format: expanded")
+
+	h.press("J")
+	h.assertContains(`{"level":"INFO","message":"payment captured","amount":42}`)
+	h.assertNotContains("format:")
+
+	// A single event is formatted from the top of the viewport.
+	h.send(tea.WindowSizeMsg{Width: 160, Height: 5})
+	h.model.logEvents.SetYOffset(h.model.logEventOffsets[1])
+	h.press("e")
+
+	if format := h.model.logEventFormat(1); format != logFormatCompact {
+		t.Fatalf("event format is %s, want compact", format)
+	}
+
+	if format := h.model.logEventFormat(2); format != logFormatRaw {
+		t.Fatalf("format of other event is %s, want raw", format)
+	}
+}
+
+func TestExportLogEvents(t *testing.T) {
+	h := newTestHarness(t, newPagedBackend())
+	path := filepath.Join(t.TempDir(), "events.csv")
+
+	h.press("l", "enter", "x")
+	h.assertContains("export to:", ".log", "format: text")
+
+	h.press("ctrl+u")
+	for _, r := range path {
+		h.press(string(r))
+	}
+	h.assertContains("format: csv")
+
+	// All events are exported, not only the pages loaded so far.
+	h.press("enter")
+	h.assertView(viewLogEvent)
+	h.assertContains("exported 5 events", "older events available")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 6 || lines[0] != "timestamp,ingestion_time,message" {
+		t.Fatalf("unexpected export:\n%s", content)
+	}
+
+	for i, line := range lines[1:] {
+		if !strings.HasSuffix(line, fmt.Sprintf(",event %d", i)) {
+			t.Fatalf("line %d: got %q, want event %d", i+1, line, i)
+		}
+	}
+
+	// Text exports hold the timestamp, ingestion time and message.
+	path = filepath.Join(t.TempDir(), "events.log")
+	h.press("x", "ctrl+u")
+	for _, r := range path {
+		h.press(string(r))
+	}
+	h.press("enter")
+	h.assertContains("exported 5 events")
+
+	content, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading export: %v", err)
+	}
+
+	if want := "2024-09-01T12:04:00.000Z\t2024-09-01T12:04:01.000Z\tevent 0\n"; !strings.HasPrefix(string(content), want) {
+		t.Fatalf("got text export\n%s\nwant it to start with %q", content, want)
+	}
+}
+
+func TestCachedLambdaDetail(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+	h.sess.cache = newLambdaCache("", time.Hour, envMasker{})
+	h.model.cache = h.sess.cache
+
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+	h.assertNotContains("cached")
+
+	// Switching to another function and back shows the cached details.
+	h.press("esc", "down", "enter", "esc", "up", "enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains("TABLE_NAME", "cached")
+	if got := backend.calls["GetFunction"]; got != 2 {
+		t.Fatalf("got %d GetFunction calls, want 2", got)
+	}
+
+	// Stale details are refreshed in the background.
+	h.sess.cache.ttl = 0
+	h.press("esc", "down", "enter")
+	h.assertContains("QUEUE_URL")
+	h.assertNotContains("cached")
+	if got := backend.calls["GetFunction"]; got != 3 {
+		t.Fatalf("got %d GetFunction calls, want 3", got)
+	}
+
+	// Refreshing all drops the cached details.
+	h.press("esc", "R", "enter")
+	h.assertView(viewLambdaDetail)
+	h.assertNotContains("cached")
+	if got := backend.calls["GetFunction"]; got != 4 {
+		t.Fatalf("got %d GetFunction calls, want 4", got)
+	}
+}
+
+func TestCachedLambdaDetailSecrets(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", env: map[string]string{"API_TOKEN": "t0ken"}})
+	backend.addFunction(fakeFunction{name: "payments-worker"})
+	h := newTestHarness(t, backend)
+
+	dir, secrets := t.TempDir(), envMasker{mode: maskSecrets, patterns: defaultSecretPatterns}
+	h.sess.cache = newLambdaCache(dir, time.Hour, secrets)
+	h.press("enter", "esc")
+
+	// A restarted program only knows the persisted details, whose secrets
+	// are fetched in the background.
+	h.sess.cache = newLambdaCache(dir, time.Hour, secrets)
+	h.model.cache = h.sess.cache
+	h.press("down", "enter", "esc", "up", "enter")
+	if got := backend.calls["GetFunction"]; got != 3 {
+		t.Fatalf("got %d GetFunction calls, want 3", got)
+	}
+
+	h.press("X")
+	h.assertContains("t0ken")
+
+	h.model.lambdaDetailInfo.envIncomplete = true
+	h.press("E")
+	if !strings.Contains(h.model.err, "still being loaded") {
+		t.Fatalf("got error %q", h.model.err)
+	}
+}
+
+func TestCachedLambdaList(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+	h.sess.cache = newLambdaCache("", time.Hour, envMasker{})
+	h.model.cache = h.sess.cache
+	h.model.lambdasCachedAt = time.Now().Add(-2 * time.Hour)
+	h.model.lambdas.Title = h.model.lambdasTitle()
+	h.assertContains("cached 2h ago, stale")
+
+	// Refreshes of another session are dropped.
+	h.send(lambdaListRefreshMsg{account: "other", region: "eu-central-1"})
+	h.assertContains("cached 2h ago", "orders-api")
+
+	h.send(handleRequest(context.Background(), &h.sess, lambdaListRefreshReq{}))
+	h.assertView(viewLambda)
+	h.assertContains("orders-api", "payments-worker")
+	h.assertNotContains("cached")
+
+	if _, _, ok := h.sess.cache.functions(h.sess.cacheAccount(), h.sess.region, nil); !ok {
+		t.Fatal("expected the refreshed functions to be cached")
+	}
+}
+
+func TestCachedLambdaListRefreshFailed(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+	h.sess.cache = newLambdaCache("", time.Hour, envMasker{})
+	h.model.cache = h.sess.cache
+	h.model.lambdasCachedAt = time.Now().Add(-2 * time.Hour)
+	h.model.lambdas.Title = h.model.lambdasTitle()
+
+	backend.errs["ListFunctions"] = errors.New("ExpiredTokenException")
+	h.send(handleRequest(context.Background(), &h.sess, lambdaListRefreshReq{}))
+	h.assertContains("Refreshing the cached functions failed", "ExpiredTokenException")
+
+	h.press("enter")
+	h.assertView(viewLambda)
+	h.assertContains("cached 2h ago, refresh failed", "orders-api")
+	h.assertNotContains("refreshing")
+}
+
+func TestCachedLambdaDetailRefreshFailed(t *testing.T) {
+	backend := newScriptedBackend()
+	h := newTestHarness(t, backend)
+	h.sess.cache = newLambdaCache("", 0, envMasker{})
+	h.model.cache = h.sess.cache
+
+	h.press("enter", "esc", "down", "enter", "esc", "up")
+	backend.errs["GetFunction"] = errors.New("ThrottlingException")
+	h.press("enter")
+	h.assertContains("Refreshing the cached details of orders-api failed", "ThrottlingException")
+
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+	h.assertContains("TABLE_NAME", "refresh failed")
+	h.assertNotContains("refreshing")
+
+	// Details refreshed later are no longer marked.
+	delete(backend.errs, "GetFunction")
+	h.press("esc", "down", "enter")
+	h.assertContains("QUEUE_URL")
+	h.assertNotContains("refresh failed")
+}
+
+func TestTriggers(t *testing.T) {
+	backend := newPagedBackend()
+	backend.addFunction(fakeFunction{
+		name:    "events-consumer",
+		runtime: lambdatypes.RuntimeNodejs20x,
+		eventSources: []string{
+			"arn:aws:sqs:eu-central-1:123456789012:orders-queue",
+			"arn:aws:kinesis:eu-central-1:123456789012:stream/clicks",
+			"arn:aws:dynamodb:eu-central-1:123456789012:table/orders/stream/2024-09-01T00:00:00.000",
+		},
+		policy: `{"Version":"2012-10-17","Statement":[{
+			"Sid":"apigw","Effect":"Allow","Principal":{"Service":"apigateway.amazonaws.com"},
+			"Action":"lambda:InvokeFunction","Resource":"*",
+			"Condition":{"ArnLike":{"AWS:SourceArn":"arn:aws:execute-api:eu-central-1:123456789012:abc/*"}}
+		}]}`,
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("down", "enter", "t")
+	h.assertView(viewTriggers)
+	h.assertContains("SQS", "orders-queue", "Kinesis", "stream/clicks", "DynamoDB", "Enabled", "API Gateway", "execute-api", "apigw")
+
+	h.press("esc")
+	h.assertView(viewLambdaDetail)
+
+	// Functions without a policy have no permissions.
+	h.press("esc", "up", "enter", "t")
+	h.assertView(viewTriggers)
+	if strings.Count(h.model.View(), "none") != 2 {
+		t.Fatalf("expected no mappings and permissions:\n%s", h.model.View())
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{
+		name:        "orders-api",
+		runtime:     lambdatypes.RuntimeNodejs20x,
+		versions:    []string{"1", "2"},
+		aliases:     []fakeAlias{{name: "prod", version: "2"}},
+		provisioned: map[string]int32{"1": 3},
+	})
+	h := newTestHarness(t, backend)
+	h.sess.cache = newLambdaCache("", time.Hour, envMasker{})
+
+	h.press("enter")
+	h.assertContains("Reserved Concurrency", "unreserved")
+
+	h.press("C")
+	h.assertView(viewConcurrency)
+	h.assertContains("unreserved", "Qualifier", "READY")
+
+	h.press("e", "1", "0", "enter")
+	h.assertView(viewConcurrencyConfirm)
+	h.assertContains(`Reserve 10 concurrent executions for "orders-api"?`)
+
+	h.press("enter")
+	h.assertView(viewConcurrency)
+	if reserved := backend.functions[0].reserved; reserved == nil || *reserved != 10 {
+		t.Fatalf("got reserved concurrency %v, want 10", reserved)
+	}
+
+	info, _, _ := h.sess.cache.details(h.sess.cacheAccount(), h.sess.region, "orders-api")
+	if info.reservedConcurrency == nil || *info.reservedConcurrency != 10 {
+		t.Fatalf("got cached reserved concurrency %v, want 10", info.reservedConcurrency)
+	}
+
+	h.press("esc")
+	h.assertView(viewLambdaDetail)
+	h.assertNotContains("unreserved")
+
+	h.press("C", "a")
+	for _, r := range "prod=5" {
+		h.press(string(r))
+	}
+	h.press("enter")
+	h.assertContains(`Provision 5 concurrent executions for "orders-api:prod"?`)
+
+	h.press("y")
+	h.assertView(viewConcurrency)
+	h.assertContains("prod")
+
+	// Configs are ordered by qualifier after the reserved concurrency.
+	h.press("down", "d")
+	h.assertContains(`Remove the provisioned concurrency of "orders-api:1"?`)
+
+	h.press("enter")
+	if want := map[string]int32{"prod": 5}; !maps.Equal(backend.functions[0].provisioned, want) {
+		t.Fatalf("got provisioned concurrency %v, want %v", backend.functions[0].provisioned, want)
+	}
+
+	h.press("up", "e", "ctrl+u", "-", "1", "enter")
+	if !strings.Contains(h.model.err, "at least 0") {
+		t.Fatalf("expected a validation error, got %q", h.model.err)
+	}
+
+	// The prompt stays open to correct the input.
+	h.press("esc")
+	h.assertView(viewConcurrency)
+	h.assertContains("reserve: -1")
+
+	h.press("esc", "d", "y")
+	if backend.functions[0].reserved != nil {
+		t.Fatalf("expected the reserved concurrency to be removed")
+	}
+}
+
+func TestInvocations(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/09/01/[$LATEST]aaaa",
+		events: []fakeEvent{
+			{timestamp: 1725192000000, message: "START RequestId: first-request Version: $LATEST"},
+			{timestamp: 1725192000100, message: "order created"},
+			{timestamp: 1725192000200, message: "END RequestId: first-request"},
+			{timestamp: 1725192000300, message: "REPORT RequestId: first-request\tDuration: 12.00 ms\tBilled Duration: 13 ms\tMemory Size: 128 MB\tMax Memory Used: 64 MB\tInit Duration: 180.00 ms"},
+			{timestamp: 1725192001000, message: "START RequestId: second-request Version: $LATEST"},
+			{timestamp: 1725192001100, message: "[ERROR] payment declined"},
+			{timestamp: 1725192001200, message: "END RequestId: second-request"},
+			{timestamp: 1725192001300, message: "REPORT RequestId: second-request\tDuration: 5.00 ms\tBilled Duration: 5 ms\tMemory Size: 128 MB\tMax Memory Used: 65 MB"},
+		},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("l", "enter", "i")
+	h.assertView(viewInvocations)
+	h.assertContains("second-request  error", "first-request  ok", "init 180.00 ms", "memory 64 MB/128 MB")
+
+	// The most recent invocation is listed first.
+	h.press("enter")
+	h.assertView(viewInvocationEvents)
+	h.assertContains("payment declined")
+	if strings.Contains(h.model.View(), "order created") {
+		t.Fatalf("expected only the events of the second invocation:\n%s", h.model.View())
+	}
+
+	h.press("esc", "down", "enter")
+	h.assertContains("order created", "first-request")
+
+	h.press("esc", "esc")
+	h.assertView(viewLogEvent)
+}
+
+func TestInvocationsWhileLoadingEvents(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/09/01/[$LATEST]aaaa",
+		events: []fakeEvent{
+			{timestamp: 1725192001000, message: "START RequestId: second-request Version: $LATEST"},
+			{timestamp: 1725192001100, message: "payment declined"},
+			{timestamp: 1725192001300, message: "REPORT RequestId: second-request\tDuration: 5.00 ms\tBilled Duration: 5 ms\tMemory Size: 128 MB\tMax Memory Used: 65 MB"},
+		},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("l", "enter", "f", "i")
+	h.assertView(viewInvocations)
+
+	// Events loaded while the invocations are listed don't change their
+	// events.
+	h.send(logEventPageMsg{
+		region:    h.model.activeLogRegion,
+		logGroup:  h.model.activeLogGroup,
+		logStream: h.model.activeLogStream,
+		events:    [][]string{{"2024-09-01T12:00:00.000Z", "START RequestId: first-request Version: $LATEST"}, {"2024-09-01T12:00:00.100Z", "order created"}},
+	})
+	h.press("enter")
+	h.assertContains("payment declined")
+	h.assertNotContains("order created")
+
+	// Following resumes when returning to the log events.
+	backend.appendEvents(fakeLogGroup("orders-api"), "2024/09/01/[$LATEST]aaaa", fakeEvent{timestamp: 1725192003000, message: "late event"})
+	h.send(followTickMsg{id: h.model.followId})
+	h.press("esc", "esc")
+	h.assertView(viewLogEvent)
+	h.assertContains("late event", "following")
+}
+
+func TestAnalytics(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x, memory: 1024})
+	for i, report := range []string{
+		"REPORT RequestId: a\tDuration: 120.00 ms\tBilled Duration: 120 ms\tMemory Size: 1024 MB\tMax Memory Used: 90 MB\tInit Duration: 300.00 ms",
+		"REPORT RequestId: b\tDuration: 15.00 ms\tBilled Duration: 15 ms\tMemory Size: 1024 MB\tMax Memory Used: 95 MB",
+	} {
+		backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+			name: fmt.Sprintf("2024/09/01/[$LATEST]%d", i),
+			events: []fakeEvent{
+				{timestamp: int64(1725192000000 + i*1000), message: fmt.Sprintf("START RequestId: %c Version: $LATEST", 'a'+i)},
+				{timestamp: int64(1725192000100 + i*1000), message: report},
+			},
+		})
+	}
+	h := newTestHarness(t, backend)
+
+	h.press("A")
+	h.assertView(viewAnalytics)
+	h.assertContains("2 invocations reported", "2 log streams", "1 of 2 invocations (50.0%)", "p50 300 ms", "Memory Utilisation of 1024 MB", "128 MB would suffice")
+
+	h.press("esc")
+	h.assertView(viewLambda)
+}
+
+func TestFilterLogEvents(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.pageSize = 2
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+
+	now := time.Now().UnixMilli()
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/09/01/[$LATEST]aaaaaaaaaaaa",
+		events: []fakeEvent{
+			{timestamp: now - 2*time.Hour.Milliseconds(), message: "ERROR too old"},
+			{timestamp: now - 10*time.Minute.Milliseconds(), message: "ERROR first"},
+			{timestamp: now - 2*time.Minute.Milliseconds(), message: "ERROR third"},
+		},
+	})
+	backend.addStream(fakeLogGroup("orders-api"), fakeStream{
+		name: "2024/09/01/[$LATEST]bbbbbbbbbbbb",
+		events: []fakeEvent{
+			{timestamp: now - 5*time.Minute.Milliseconds(), message: "ERROR second"},
+			{timestamp: now - 4*time.Minute.Milliseconds(), message: "all good"},
+		},
+	})
+	h := newTestHarness(t, backend)
+
+	h.press("l", "s")
+	h.assertView(viewFilter)
+	h.assertContains("from:", "15m", "pattern:")
+
+	h.press("tab", "tab")
+	for _, r := range `"ERROR"` {
+		h.press(string(r))
+	}
+	h.press("enter")
+	h.assertView(viewFilterResult)
+	h.assertContains("3 events", "[$LATEST]aaaaaaaa", "[$LATEST]bbbbbbbb")
+
+	// Events of all streams are merged in chronological order.
+	messages := make([]string, 0, len(h.model.filterEvents))
+	for _, row := range h.model.filterEvents {
+		messages = append(messages, row[2])
+	}
+	if want := []string{"ERROR first", "ERROR second", "ERROR third"}; !slices.Equal(messages, want) {
+		t.Fatalf("got events %q, want %q", messages, want)
+	}
+
+	// Invalid times are reported without searching.
+	h.press("esc")
+	h.assertView(viewFilter)
+	h.press("shift+tab", "shift+tab", "ctrl+u", "x", "enter")
+	h.assertContains(`invalid time "x"`)
+
+	h.press("esc", "esc")
+	h.assertView(viewLogStream)
+}
+
+func TestCustomKeyBindings(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	keys := defaultKeyMap()
+	if err := keys.bind(map[string][]string{"back": {"ctrl+b", "h"}, "logStreams": {"s"}}); err != nil {
+		t.Fatal(err)
+	}
+	h.model.setKeyMap(keys)
+
+	h.press("s")
+	h.assertView(viewLogStream)
+
+	h.press("enter")
+	h.assertView(viewLogEvent)
+	h.assertContains("ctrl+b/h back")
+
+	h.press("esc")
+	h.assertView(viewLogEvent)
+
+	h.press("x")
+	h.assertContains("enter export • ctrl+b/h cancel")
+	h.press("ctrl+b")
+	h.assertView(viewLogEvent)
+
+	h.press("h", "ctrl+b")
+	h.assertView(viewLambda)
+	h.assertContains("s log streams")
+
+	// Keys inserting text are typed into inputs.
+	h.press("i", "h")
+	h.assertView(viewInvoke)
+	if got := h.model.invokePayload.Value(); got != "{}h" {
+		t.Fatalf("payload is %q, want {}h", got)
+	}
+
+	h.press("ctrl+b")
+	h.assertView(viewLambda)
+
+	h.press("enter", "t")
+	h.assertView(viewTriggers)
+	h.assertContains("↑/k scroll up • ↓/j scroll down • ctrl+b/h back")
+}
+
+func TestCustomKeyBindingsValidation(t *testing.T) {
+	keys := defaultKeyMap()
+	if err := keys.bind(map[string][]string{"follow": {"j"}}); err == nil || !strings.Contains(err.Error(), `"follow" and "down" of log events`) {
+		t.Fatalf("got error %v, want a conflict with scrolling the log events", err)
+	}
+}
+
+func TestListFilterCapturesQuit(t *testing.T) {
+	h := newTestHarness(t, newScriptedBackend())
+
+	h.press("/", "q")
+	h.assertView(viewLambda)
+	if got := h.model.lambdas.FilterValue(); got != "q" {
+		t.Fatalf("filter is %q, want q", got)
+	}
+}
+
+// lineOf returns the line of the view containing s.
+func (h *testHarness) lineOf(s string) int {
+	h.t.Helper()
+
+	for i, line := range strings.Split(h.model.View(), "\n") {
+		if strings.Contains(line, s) {
+			return i
+		}
+	}
+
+	h.t.Fatalf("view does not contain %q:\n%s", s, h.model.View())
+
+	return -1
+}
+
+func TestMouse(t *testing.T) {
+	backend := newFakeBackend("eu-central-1")
+	backend.addFunction(fakeFunction{name: "orders-api", runtime: lambdatypes.RuntimeNodejs20x})
+	backend.addFunction(fakeFunction{name: "payments-worker", runtime: lambdatypes.RuntimeNodejs20x})
+	backend.addStream(fakeLogGroup("payments-worker"), fakeStream{name: "stream-a", events: []fakeEvent{{timestamp: 1, message: "event a"}}})
+	backend.addStream(fakeLogGroup("payments-worker"), fakeStream{name: "stream-b", events: []fakeEvent{{timestamp: 2, message: "event b"}}})
+	h := newTestHarness(t, backend)
+
+	click := func(y int, button tea.MouseButton) {
+		h.send(tea.MouseMsg{X: 10, Y: y, Action: tea.MouseActionPress, Button: button})
+	}
+
+	click(h.lineOf("payments-worker"), tea.MouseButtonLeft)
+	h.assertView(viewLambda)
+	if item := h.model.lambdas.SelectedItem().(lambdaItem); item.name != "payments-worker" {
+		t.Fatalf("clicked %q, want payments-worker", item.name)
+	}
+
+	h.press("l")
+	h.assertView(viewLogStream)
+
+	// A double click opens the item like the choose key.
+	y := h.lineOf("stream-a")
+	click(y, tea.MouseButtonLeft)
+	h.assertView(viewLogStream)
+	click(y, tea.MouseButtonLeft)
+	h.assertView(viewLogEvent)
+	h.assertContains("event a")
+
+	// The back button of the mouse goes back in every view.
+	click(0, tea.MouseButtonBackward)
+	h.assertView(viewLogStream)
+
+	// The title of a list goes back like a breadcrumb.
+	click(h.lineOf("Log Streams"), tea.MouseButtonLeft)
+	h.assertView(viewLambda)
+
+	h.press("enter")
+	h.assertView(viewLambdaDetail)
+	h.send(tea.MouseMsg{Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	h.assertView(viewLambdaDetail)
+
+	h.send(tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !h.model.mouseDisabled {
+		t.Fatal("mouse capture was not disabled")
+	}
+}
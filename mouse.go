@@ -0,0 +1,148 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// doubleClickInterval is the maximum time between the clicks of a double
+// click.
+const doubleClickInterval = 500 * time.Millisecond
+
+// A mouseClick is a click on an item of the list of a view.
+type mouseClick struct {
+	view string
+	item int
+	at   time.Time
+}
+
+// onMouse handles clicks on lists and the back button of the mouse. Clicks
+// that trigger an action are translated into the key message of its binding,
+// so that they behave like the key in every view. Messages that are not
+// handled are returned unchanged and nil once a click has been handled.
+func (m *model) onMouse(msg tea.MouseMsg) tea.Msg {
+	if msg.Action != tea.MouseActionPress {
+		return msg
+	}
+
+	if msg.Button == tea.MouseButtonBackward {
+		return bindingMsg(m.keys.back)
+	}
+
+	l, delegate := m.activeList()
+	if l == nil || msg.Button != tea.MouseButtonLeft || l.FilterState() == list.Filtering {
+		return msg
+	}
+
+	// Lists are centred vertically.
+	line := msg.Y - (m.winHeight-l.Height())/2
+
+	// The title of a list leads back to the previous view, like a breadcrumb.
+	if line == 0 && m.activeView != viewLambda {
+		return bindingMsg(m.keys.back)
+	}
+
+	item := listItemAt(*l, delegate, line)
+	if item < 0 {
+		return nil
+	}
+
+	l.Select(item)
+
+	click := mouseClick{view: m.activeView, item: item, at: time.Now()}
+	if m.lastClick.view == click.view && m.lastClick.item == click.item && click.at.Sub(m.lastClick.at) <= doubleClickInterval {
+		m.lastClick = mouseClick{}
+
+		return bindingMsg(m.keys.choose)
+	}
+
+	m.lastClick = click
+
+	return nil
+}
+
+// activeList returns the list of the active view along with its delegate, or
+// nil if the view shows no list.
+func (m *model) activeList() (*list.Model, list.DefaultDelegate) {
+	delegate := newListDelegate()
+
+	switch m.activeView {
+	case viewLambda:
+		return &m.lambdas, delegate
+	case viewLogStream:
+		return &m.logStreams, delegate
+	case viewVersions:
+		return &m.versions, delegate
+	case viewInvocations:
+		return &m.invocations, delegate
+	case viewProfile:
+		return &m.profiles, delegate
+	case viewRegion:
+		delegate.ShowDescription = false
+
+		return &m.regions, delegate
+	default:
+		return nil, delegate
+	}
+}
+
+// listItemAt returns the index of the visible item of a list rendered at the
+// given line of the list, or -1 if there is none.
+func listItemAt(l list.Model, delegate list.DefaultDelegate, line int) int {
+	if l.ShowTitle() || (l.ShowFilter() && l.FilteringEnabled()) {
+		line -= 1 + l.Styles.TitleBar.GetVerticalFrameSize()
+	}
+
+	if l.ShowStatusBar() {
+		line -= 1 + l.Styles.StatusBar.GetVerticalFrameSize()
+	}
+
+	step := delegate.Height() + delegate.Spacing()
+	// Clicks on the spacing between items select nothing.
+	if line < 0 || line%step >= delegate.Height() {
+		return -1
+	}
+
+	item := line / step
+	if item >= l.Paginator.ItemsOnPage(len(l.VisibleItems())) {
+		return -1
+	}
+
+	return l.Paginator.Page*l.Paginator.PerPage + item
+}
+
+// toggleMouse enables or disables capturing the mouse. Terminals only allow
+// selecting text while the mouse is not captured.
+func (m *model) toggleMouse() tea.Cmd {
+	m.mouseDisabled = !m.mouseDisabled
+	if m.mouseDisabled {
+		return tea.DisableMouse
+	}
+
+	return tea.EnableMouseCellMotion
+}
+
+// bindingMsg returns the key message of the first key of a binding.
+func bindingMsg(b key.Binding) tea.KeyMsg {
+	name := b.Keys()[0]
+
+	alt := false
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		name, alt = rest, true
+	}
+
+	// Key types are numbered from the negative special keys up to the
+	// control characters, the last of which is backspace. Spaces are typed
+	// like runes.
+	for t := tea.KeyType(-100); t <= tea.KeyBackspace; t++ {
+		if t != tea.KeyRunes && t != tea.KeySpace && (tea.Key{Type: t}).String() == name {
+			return tea.KeyMsg{Type: t, Alt: alt}
+		}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name), Alt: alt}
+}
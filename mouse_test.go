@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestBindingMsg(t *testing.T) {
+	keys := defaultKeyMap()
+	keys.back = newBinding("back", "alt+h")
+
+	for _, action := range keys.actions() {
+		if msg := bindingMsg(*action.binding); !key.Matches(msg, *action.binding) {
+			t.Errorf("message %q does not match the binding %q", msg, action.name)
+		}
+	}
+}
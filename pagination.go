@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A log stream page request fetches the streams following nextToken.
+type logStreamPageReq struct {
+	region    string
+	logGroup  string
+	versions  []string
+	nextToken string
+}
+
+type logStreamPageMsg struct {
+	region    string
+	logGroup  string
+	versions  []string
+	items     []logStream
+	nextToken string
+}
+
+// A log event page request fetches the events preceding backwardToken.
+type logEventPageReq struct {
+	region        string
+	logGroup      string
+	logStream     string
+	backwardToken string
+}
+
+type logEventPageMsg struct {
+	region        string
+	logGroup      string
+	logStream     string
+	events        [][]string
+	backwardToken string
+}
+
+var expiredLogStreamStyle = lipgloss.NewStyle()
+
+// loadMoreLogStreams requests the next page of log streams if there is one
+// and the selection is at the end of the list.
+func (m *model) loadMoreLogStreams() {
+	if m.logStreamsNextToken == "" || m.loadingMoreLogStreams || m.logStreams.FilterState() != list.Unfiltered {
+		return
+	}
+
+	if m.logStreams.Index() < len(m.logStreams.Items())-1 {
+		return
+	}
+
+	select {
+	case m.reqCh <- logStreamPageReq{
+		region:    m.activeLogRegion,
+		logGroup:  m.activeLogGroup,
+		versions:  m.logStreamVersions,
+		nextToken: m.logStreamsNextToken,
+	}:
+		m.loadingMoreLogStreams = true
+		m.logStreams.Title = m.logStreamsTitle()
+	default:
+	}
+}
+
+// loadMoreLogEvents requests the events preceding the loaded ones if there
+// are any and the viewport is scrolled to the top.
+func (m *model) loadMoreLogEvents() {
+	if m.logEventsBackwardToken == "" || m.loadingMoreLogEvents || !m.logEvents.AtTop() {
+		return
+	}
+
+	select {
+	case m.reqCh <- logEventPageReq{
+		region:        m.activeLogRegion,
+		logGroup:      m.activeLogGroup,
+		logStream:     m.activeLogStream,
+		backwardToken: m.logEventsBackwardToken,
+	}:
+		m.loadingMoreLogEvents = true
+	default:
+	}
+}
+
+func (m *model) onRcvLogStreamPageMsg(msg logStreamPageMsg) {
+	// The user may have left the log group while the page was loading.
+	if msg.logGroup != m.activeLogGroup || msg.region != m.activeLogRegion || !slices.Equal(msg.versions, m.logStreamVersions) {
+		return
+	}
+
+	m.loadingMoreLogStreams = false
+	m.logStreamsNextToken = msg.nextToken
+	m.logStreams.SetItems(append(m.logStreams.Items(), logStreamItems(msg.items)...))
+	m.logStreams.Title = m.logStreamsTitle()
+}
+
+func (m *model) onRcvLogEventPageMsg(msg logEventPageMsg) {
+	if msg.logGroup != m.activeLogGroup || msg.region != m.activeLogRegion || msg.logStream != m.activeLogStream {
+		return
+	}
+
+	m.loadingMoreLogEvents = false
+	m.logEventsBackwardToken = msg.backwardToken
+
+	n := len(msg.events)
+	if n == 0 {
+		return
+	}
+
+	m.logEventRows = append(msg.events, m.logEventRows...)
+
+	formats := make(map[int]logFormat, len(m.logEventFormats))
+	for row, format := range m.logEventFormats {
+		formats[row+n] = format
+	}
+	m.logEventFormats = formats
+	m.searchPrependedLogEvents(n)
+
+	// Keep the previously loaded events in place.
+	yOffset := m.logEvents.YOffset
+	m.renderLogEvents()
+	m.logEvents.SetYOffset(yOffset + m.logEventOffsets[n])
+}
+
+func logStreamItems(streams []logStream) []list.Item {
+	items := make([]list.Item, 0, len(streams))
+
+	for _, stream := range streams {
+		description := fmt.Sprintf("Last Event: %s", stream.lastEventTimestamp)
+		if stream.expired {
+			description = expiredLogStreamStyle.Render("Expired")
+		}
+
+		items = append(items, logStreamItem{stream.name, description})
+	}
+
+	return items
+}
+
+func (m model) logStreamsTitle() string {
+	logGroup := fmt.Sprintf("Log Group \"%s\"", m.activeLogGroup)
+	if m.logStreamQualifier != "" {
+		logGroup += fmt.Sprintf(" (%s)", m.logStreamQualifier)
+	}
+
+	title := fmt.Sprintf("Viewing Log Streams - %s - %s", logGroup, m.sessionTitle())
+
+	switch {
+	case m.loadingMoreLogStreams:
+		return title + " - loading more..."
+	case m.logStreamsNextToken != "":
+		return title + " - more available"
+	default:
+		return title
+	}
+}
+
+// logEventPagingStatusView tells whether older events can be loaded by
+// scrolling to the top.
+func (m model) logEventPagingStatusView() string {
+	switch {
+	case m.loadingMoreLogEvents:
+		return "loading older events..."
+	case m.logEventsBackwardToken != "":
+		return "older events available"
+	default:
+		return ""
+	}
+}
+
+// isPagingInput reports whether msg may scroll a list or viewport towards
+// more data.
+func isPagingInput(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		return true
+	default:
+		return false
+	}
+}
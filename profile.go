@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// awsRegions lists the regions offered by the region picker.
+var awsRegions = []string{
+	"us-east-1",
+	"us-east-2",
+	"us-west-1",
+	"us-west-2",
+	"af-south-1",
+	"ap-east-1",
+	"ap-south-1",
+	"ap-south-2",
+	"ap-southeast-1",
+	"ap-southeast-2",
+	"ap-southeast-3",
+	"ap-southeast-4",
+	"ap-northeast-1",
+	"ap-northeast-2",
+	"ap-northeast-3",
+	"ca-central-1",
+	"ca-west-1",
+	"eu-central-1",
+	"eu-central-2",
+	"eu-west-1",
+	"eu-west-2",
+	"eu-west-3",
+	"eu-south-1",
+	"eu-south-2",
+	"eu-north-1",
+	"il-central-1",
+	"me-south-1",
+	"me-central-1",
+	"sa-east-1",
+}
+
+type awsProfile struct {
+	name   string
+	region string
+}
+
+type profileListReq struct{}
+
+type profileListMsg struct {
+	profiles []awsProfile
+}
+
+type sessionReq struct {
+	profile string
+	region  string
+	// regions are listed instead of region in multi-region mode.
+	regions []string
+}
+
+type sessionMsg struct {
+	profile   string
+	region    string
+	accountId string
+	items     []list.Item
+}
+
+type profileItem struct {
+	profile awsProfile
+}
+
+func (p profileItem) Title() string {
+	return p.profile.name
+}
+
+func (p profileItem) Description() string {
+	if p.profile.region == "" {
+		return "No default region"
+	}
+
+	return "Region: " + p.profile.region
+}
+
+func (p profileItem) FilterValue() string {
+	return p.profile.name
+}
+
+type regionItem string
+
+func (r regionItem) Title() string {
+	return string(r)
+}
+
+func (r regionItem) Description() string {
+	return ""
+}
+
+func (r regionItem) FilterValue() string {
+	return string(r)
+}
+
+// listProfiles collects the profiles of the shared config and credentials
+// files, sorted by name.
+func listProfiles() ([]awsProfile, error) {
+	configFile := os.Getenv("AWS_CONFIG_FILE")
+	if configFile == "" {
+		configFile = config.DefaultSharedConfigFilename()
+	}
+
+	credentialsFile := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.DefaultSharedCredentialsFilename()
+	}
+
+	profiles := map[string]awsProfile{}
+
+	if err := readProfiles(configFile, true, profiles); err != nil {
+		return nil, err
+	}
+
+	if err := readProfiles(credentialsFile, false, profiles); err != nil {
+		return nil, err
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profiles found in %s or %s", configFile, credentialsFile)
+	}
+
+	result := make([]awsProfile, 0, len(profiles))
+	for _, profile := range profiles {
+		result = append(result, profile)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+
+	return result, nil
+}
+
+// readProfiles adds the profile sections of an ini file to profiles. In the
+// shared config file, profiles other than the default one are prefixed with
+// "profile ". A missing file is not an error.
+func readProfiles(path string, isConfig bool, profiles map[string]awsProfile) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	current := ""
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section := strings.TrimSpace(line[1 : len(line)-1])
+			current = ""
+
+			if isConfig && section != "default" {
+				name, ok := strings.CutPrefix(section, "profile ")
+				if !ok {
+					// sso-session, services and other non profile sections
+					continue
+				}
+
+				section = strings.TrimSpace(name)
+			}
+
+			current = section
+			if _, ok := profiles[current]; !ok {
+				profiles[current] = awsProfile{name: current}
+			}
+		case current != "":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || strings.TrimSpace(key) != "region" {
+				continue
+			}
+
+			profile := profiles[current]
+			profile.region = strings.TrimSpace(value)
+			profiles[current] = profile
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (m model) viewProfileUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.profiles.FilterState() == list.Filtering {
+		m.profiles, cmd = m.profiles.Update(msg)
+
+		return m, cmd
+	}
+
+	m.profiles, cmd = m.profiles.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			if m.profiles.FilterValue() == "" {
+				m.activeView = viewLambda
+			}
+			m.profiles.ResetFilter()
+			cmd = nil
+		case key.Matches(msg, m.keys.choose):
+			item, ok := m.profiles.SelectedItem().(profileItem)
+			if !ok {
+				break
+			}
+
+			region := m.region
+			if item.profile.region != "" {
+				region = item.profile.region
+			}
+
+			m.selectedProfile = item.profile.name
+			m.regions.ResetFilter()
+			for i, r := range awsRegions {
+				if r == region {
+					m.regions.Select(i)
+				}
+			}
+
+			m.regions.Title = fmt.Sprintf("Select Region - Profile: %s", m.selectedProfile)
+			m.activeView = viewRegion
+			cmd = nil
+		}
+	}
+
+	return m, cmd
+}
+
+func (m model) viewRegionUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.regions.FilterState() == list.Filtering {
+		m.regions, cmd = m.regions.Update(msg)
+
+		return m, cmd
+	}
+
+	m.regions, cmd = m.regions.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			if m.regions.FilterValue() == "" {
+				m.activeView = viewProfile
+			}
+			m.regions.ResetFilter()
+			cmd = nil
+		case key.Matches(msg, m.keys.choose):
+			item, ok := m.regions.SelectedItem().(regionItem)
+			if !ok {
+				break
+			}
+
+			select {
+			case m.reqCh <- m.tag(sessionReq{profile: m.selectedProfile, region: string(item), regions: m.lambdaListRegions()}):
+				cmd = m.startLoading()
+			default:
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvProfileListMsg(msg profileListMsg) {
+	items := make([]list.Item, 0, len(msg.profiles))
+	selected := 0
+	for i, profile := range msg.profiles {
+		items = append(items, profileItem{profile})
+
+		if profile.name == m.profile {
+			selected = i
+		}
+	}
+
+	m.profiles.ResetFilter()
+	m.profiles.SetItems(items)
+	m.profiles.Select(selected)
+	m.activeView = viewProfile
+	m.loading = false
+}
+
+func (m *model) onRcvSessionMsg(msg sessionMsg) {
+	m.profile = msg.profile
+	m.region = msg.region
+	m.accountId = msg.accountId
+	m.activeLambda = ""
+	m.activeLogGroup = ""
+	m.activeLogStream = ""
+	m.activeLambdaRegion = ""
+	m.activeLogRegion = ""
+	m.invokeTarget = ""
+	m.invokeRegion = ""
+	m.stopFollow()
+
+	m.lambdas.ResetFilter()
+	m.lambdas.SetItems(msg.items)
+	m.lambdas.Select(0)
+	m.lambdasCachedAt = time.Time{}
+	m.lambdas.Title = m.lambdasTitle()
+	m.activeView = viewLambda
+	m.loading = false
+}
+
+func newRegionList() list.Model {
+	items := make([]list.Item, 0, len(awsRegions))
+	for _, region := range awsRegions {
+		items = append(items, regionItem(region))
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+
+	return list.New(items, delegate, 0, 0)
+}
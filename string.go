@@ -15,6 +15,12 @@ func wrapString(str string, length int) string {
 	for _, c := range str {
 		b.WriteRune(c)
 
+		if c == '\n' {
+			count = 0
+
+			continue
+		}
+
 		if count == length {
 			b.WriteRune('\n')
 			count = 0
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A theme holds the colours of the user interface. Colours are ANSI numbers
+// like "241" or hex values like "#3275C4", an empty colour leaves the colour
+// of the terminal.
+type theme struct {
+	// Accent highlights timestamps, JSON keys, sparklines and the selected
+	// field of a table.
+	Accent lipgloss.Color `json:"accent"`
+	// Text is the foreground of table cells with a background.
+	Text lipgloss.Color `json:"text"`
+	// SelectedText is the foreground of cells with the accent as background.
+	SelectedText lipgloss.Color `json:"selectedText"`
+	// Header and HeaderStripe are the backgrounds of table headers and field
+	// names, Surface and Stripe those of values and alternating rows.
+	Header       lipgloss.Color `json:"header"`
+	HeaderStripe lipgloss.Color `json:"headerStripe"`
+	Surface      lipgloss.Color `json:"surface"`
+	Stripe       lipgloss.Color `json:"stripe"`
+	Muted        lipgloss.Color `json:"muted"`
+	Success      lipgloss.Color `json:"success"`
+	Warning      lipgloss.Color `json:"warning"`
+	Error        lipgloss.Color `json:"error"`
+	Info         lipgloss.Color `json:"info"`
+	// Literal colours JSON booleans and null.
+	Literal lipgloss.Color `json:"literal"`
+	// Match and CurrentMatch are the backgrounds of search matches, MatchText
+	// their foreground.
+	Match        lipgloss.Color `json:"match"`
+	CurrentMatch lipgloss.Color `json:"currentMatch"`
+	MatchText    lipgloss.Color `json:"matchText"`
+	// ListTitle and ListTitleText colour the titles of lists, ListSelected and
+	// ListSelectedDesc their selected item.
+	ListTitle        lipgloss.Color `json:"listTitle"`
+	ListTitleText    lipgloss.Color `json:"listTitleText"`
+	ListSelected     lipgloss.Color `json:"listSelected"`
+	ListSelectedDesc lipgloss.Color `json:"listSelectedDesc"`
+}
+
+var darkTheme = theme{
+	Accent:           "#3275C4",
+	Header:           "#192a4a",
+	HeaderStripe:     "#20355c",
+	Surface:          "235",
+	Stripe:           "236",
+	Muted:            "241",
+	Success:          "10",
+	Warning:          "11",
+	Error:            "9",
+	Info:             "14",
+	Literal:          "13",
+	Match:            "11",
+	CurrentMatch:     "208",
+	MatchText:        "0",
+	ListTitle:        "62",
+	ListTitleText:    "230",
+	ListSelected:     "#EE6FF8",
+	ListSelectedDesc: "#AD58B4",
+}
+
+var lightTheme = theme{
+	Accent:           "#1f5fa8",
+	SelectedText:     "15",
+	Header:           "#c6d8f0",
+	HeaderStripe:     "#b3c9e8",
+	Surface:          "255",
+	Stripe:           "254",
+	Muted:            "243",
+	Success:          "28",
+	Warning:          "130",
+	Error:            "160",
+	Info:             "31",
+	Literal:          "90",
+	Match:            "220",
+	CurrentMatch:     "208",
+	MatchText:        "0",
+	ListTitle:        "62",
+	ListTitleText:    "230",
+	ListSelected:     "#A03FC0",
+	ListSelectedDesc: "#B865D0",
+}
+
+// highContrastTheme only uses the 16 basic ANSI colours, which terminals
+// usually render with a strong contrast to their background.
+var highContrastTheme = theme{
+	Accent:           "14",
+	Text:             "15",
+	SelectedText:     "0",
+	Header:           "4",
+	HeaderStripe:     "4",
+	Surface:          "0",
+	Stripe:           "8",
+	Muted:            "7",
+	Success:          "10",
+	Warning:          "11",
+	Error:            "9",
+	Info:             "14",
+	Literal:          "13",
+	Match:            "11",
+	CurrentMatch:     "10",
+	MatchText:        "0",
+	ListTitle:        "14",
+	ListTitleText:    "0",
+	ListSelected:     "11",
+	ListSelectedDesc: "11",
+}
+
+var themes = map[string]theme{
+	"dark":          darkTheme,
+	"light":         lightTheme,
+	"high-contrast": highContrastTheme,
+}
+
+// activeTheme is the theme the styles were last built from.
+var activeTheme = darkTheme
+
+// A themeFile is a custom theme, e.g.
+//
+//	{"base": "light", "accent": "#d75f00", "stripe": "255"}
+type themeFile struct {
+	// Base is the preset whose colours are used for those the file omits,
+	// dark by default.
+	Base string `json:"base"`
+}
+
+// loadTheme returns the preset of the given name or the theme of the file at
+// name. The auto theme is the dark or light preset depending on the
+// background of the terminal.
+func loadTheme(name string) (theme, error) {
+	if name == "auto" {
+		if lipgloss.HasDarkBackground() {
+			return darkTheme, nil
+		}
+
+		return lightTheme, nil
+	}
+
+	if t, ok := themes[name]; ok {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return theme{}, fmt.Errorf("unknown theme %q, expected auto, dark, light, high-contrast or a theme file: %w", name, err)
+	}
+
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return theme{}, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	if file.Base == "" {
+		file.Base = "dark"
+	}
+
+	t, ok := themes[file.Base]
+	if !ok {
+		return theme{}, fmt.Errorf("%s: unknown base theme %q", name, file.Base)
+	}
+
+	// Colours of the file replace those of the base theme.
+	if err := json.Unmarshal(data, &t); err != nil {
+		return theme{}, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return t, nil
+}
+
+// applyTheme colours the styles of all views.
+func applyTheme(t theme) {
+	activeTheme = t
+
+	logEventTimestampStyle = logEventTimestampStyle.Foreground(t.Accent)
+	lambdaDetailFieldNameStyle = lambdaDetailFieldNameStyle.Background(t.Header).Foreground(t.Text)
+	lambdaDetailFieldValueStyle = lambdaDetailFieldValueStyle.Background(t.Surface).Foreground(t.Text)
+	lambdaDetailSelectedFieldNameStyle = lambdaDetailFieldNameStyle.Background(t.Accent).Foreground(t.SelectedText)
+	spinnerStyle = spinnerStyle.Foreground(t.Accent)
+	helpStyle = helpStyle.Foreground(t.Muted)
+	errorStyle = errorStyle.BorderForeground(t.Error)
+
+	loadingHintStyle = loadingHintStyle.Foreground(t.Muted)
+	expiredLogStreamStyle = expiredLogStreamStyle.Foreground(t.Error)
+	invokeErrorStyle = invokeErrorStyle.Foreground(t.Error)
+
+	envAddedStyle = envAddedStyle.Foreground(t.Success)
+	envDeletedStyle = envDeletedStyle.Foreground(t.Error)
+	envChangedStyle = envChangedStyle.Foreground(t.Warning)
+
+	followActiveStyle = followActiveStyle.Foreground(t.Success)
+	followPausedStyle = followPausedStyle.Foreground(t.Warning)
+
+	jsonKeyStyle = jsonKeyStyle.Foreground(t.Accent)
+	jsonStringStyle = jsonStringStyle.Foreground(t.Success)
+	jsonNumberStyle = jsonNumberStyle.Foreground(t.Info)
+	jsonLiteralStyle = jsonLiteralStyle.Foreground(t.Literal)
+
+	levelColors := map[string]lipgloss.Color{
+		"TRACE": t.Muted,
+		"DEBUG": t.Info,
+		"INFO":  t.Success,
+		"WARN":  t.Warning,
+		"ERROR": t.Error,
+		"FATAL": t.Error,
+	}
+	for level, color := range levelColors {
+		logLevelStyles[level] = logLevelStyles[level].Foreground(color)
+	}
+
+	logSearchMatchStyle = logSearchMatchStyle.Background(t.Match).Foreground(t.MatchText)
+	logSearchCurrentMatchStyle = logSearchCurrentMatchStyle.Background(t.CurrentMatch).Foreground(t.MatchText)
+	logSearchErrStyle = logSearchErrStyle.Foreground(t.Error)
+
+	filterStreamStyle = filterStreamStyle.Foreground(t.Muted)
+
+	insightsSelectedRangeStyle = insightsSelectedRangeStyle.Background(t.Header).Foreground(t.Text)
+	insightsHeaderStyle = lambdaDetailFieldNameStyle
+
+	metricSummaryStyle = metricSummaryStyle.Foreground(t.Muted)
+	sparklineStyle = sparklineStyle.Foreground(t.Accent)
+	sparklineStyles["Errors"] = sparklineStyle.Foreground(t.Error)
+	sparklineStyles["Throttles"] = sparklineStyle.Foreground(t.Warning)
+
+	analyticsBarStyle = sparklineStyle
+	analyticsHintStyle = analyticsHintStyle.Foreground(t.Warning)
+}
+
+// newListDelegate returns the item delegate of lists in the colours of the
+// active theme.
+func newListDelegate() list.DefaultDelegate {
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(activeTheme.ListSelected).
+		BorderForeground(activeTheme.ListSelected)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(activeTheme.ListSelectedDesc).
+		BorderForeground(activeTheme.ListSelected)
+
+	return delegate
+}
+
+// setTheme colours the views and lists of the model with the given theme.
+func (m *model) setTheme(t theme) {
+	applyTheme(t)
+
+	for _, l := range []*list.Model{&m.lambdas, &m.logStreams, &m.versions, &m.invocations, &m.profiles, &m.regions} {
+		l.Styles.Title = l.Styles.Title.Background(t.ListTitle).Foreground(t.ListTitleText)
+	}
+
+	delegate := newListDelegate()
+	m.lambdas.SetDelegate(delegate)
+	m.logStreams.SetDelegate(delegate)
+	m.versions.SetDelegate(delegate)
+	m.invocations.SetDelegate(delegate)
+	m.profiles.SetDelegate(delegate)
+
+	delegate.ShowDescription = false
+	m.regions.SetDelegate(delegate)
+
+	m.spinner.Style = spinnerStyle
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTheme(t *testing.T) {
+	for name, want := range themes {
+		got, err := loadTheme(name)
+		if err != nil || got != want {
+			t.Errorf("loading preset %q: got %+v, %v", name, got, err)
+		}
+	}
+
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "theme.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	got, err := loadTheme(write(`{"base": "light", "accent": "#d75f00", "stripe": "255"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := lightTheme
+	want.Accent, want.Stripe = "#d75f00", "255"
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// Themes without a base extend the dark theme.
+	got, err = loadTheme(write(`{"error": "1"}`))
+	if err != nil || got.Error != "1" || got.Accent != darkTheme.Accent {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	for content, wantErr := range map[string]string{
+		`{"base": "solarized"}`: `unknown base theme "solarized"`,
+		`{"accent": 1}`:         "parsing",
+	} {
+		if _, err := loadTheme(write(content)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("loading %s: got error %v, want %q", content, err, wantErr)
+		}
+	}
+
+	if _, err := loadTheme(filepath.Join(dir, "missing.json")); err == nil || !strings.Contains(err.Error(), "unknown theme") {
+		t.Errorf("got error %v for a missing theme file", err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+type triggersReq struct {
+	region    string
+	name      string
+	qualifier string
+}
+
+type triggersMsg struct {
+	mappings    []eventSourceMapping
+	permissions []policyPermission
+}
+
+// listTriggers requests the event source mappings and resource-based policy of
+// the function shown in the detail view.
+func (m *model) listTriggers() tea.Cmd {
+	select {
+	case m.reqCh <- m.tag(triggersReq{region: m.activeLambdaRegion, name: m.activeLambda, qualifier: m.activeLambdaQualifier}):
+		return m.startLoading()
+	default:
+		return nil
+	}
+}
+
+func (m model) viewTriggersUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.triggers, cmd = m.triggers.Update(msg)
+
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.back) {
+		m.activeView = viewLambdaDetail
+		cmd = nil
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvTriggersMsg(msg triggersMsg) {
+	name := m.activeLambda
+	if m.activeLambdaQualifier != "" {
+		name += ":" + m.activeLambdaQualifier
+	}
+
+	rows := []string{
+		lambdaDetailTitleStyle.Render(fmt.Sprintf("Triggers - Function \"%s\" - %s", name, m.sessionTitle())),
+		"",
+		lambdaDetailTitleStyle.Render("Event Source Mappings"),
+	}
+
+	if len(msg.mappings) == 0 {
+		rows = append(rows, helpStyle.Render("none"))
+	} else {
+		mappingRows := make([][]string, 0, len(msg.mappings))
+		for _, mapping := range msg.mappings {
+			mappingRows = append(mappingRows, []string{
+				mapping.kind,
+				mapping.source,
+				strconv.Itoa(int(mapping.batchSize)),
+				mapping.state,
+				mapping.lastResult,
+			})
+		}
+
+		rows = append(rows, m.triggersTable([]string{"Type", "Source", "Batch Size", "State", "Last Result"}, mappingRows))
+	}
+
+	rows = append(rows, "", lambdaDetailTitleStyle.Render("Resource-Based Policy"))
+
+	if len(msg.permissions) == 0 {
+		rows = append(rows, helpStyle.Render("none"))
+	} else {
+		permissionRows := make([][]string, 0, len(msg.permissions))
+		for _, permission := range msg.permissions {
+			permissionRows = append(permissionRows, []string{permission.principal, permission.source, permission.action, permission.sid})
+		}
+
+		rows = append(rows, m.triggersTable([]string{"Principal", "Source", "Action", "Statement"}, permissionRows))
+	}
+
+	rows = append(rows, "", helpLine(keyHelp(m.triggers.KeyMap.Up, "scroll up"), keyHelp(m.triggers.KeyMap.Down, "scroll down"), keyHelp(m.keys.back, "")))
+
+	m.triggers.SetContent(lipgloss.JoinVertical(lipgloss.Left, rows...))
+	m.triggers.GotoTop()
+	m.activeView = viewTriggers
+	m.loading = false
+}
+
+func (m model) triggersTable(headers []string, rows [][]string) string {
+	return table.
+		New().
+		Border(lipgloss.HiddenBorder()).
+		StyleFunc(insightsTableStyleFunc).
+		Width(m.triggers.Width).
+		Headers(headers...).
+		Rows(rows...).
+		Render()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePolicy(t *testing.T) {
+	policy := `{"Version":"2012-10-17","Statement":[
+		{"Sid":"s3","Effect":"Allow","Principal":{"Service":"s3.amazonaws.com"},"Action":"lambda:InvokeFunction",
+			"Condition":{"StringEquals":{"AWS:SourceAccount":"123456789012"},"ArnLike":{"AWS:SourceArn":"arn:aws:s3:::uploads"}}},
+		{"Sid":"rule","Effect":"Allow","Principal":{"Service":["events.amazonaws.com","custom.example.com"]},"Action":["lambda:InvokeFunction"]},
+		{"Sid":"url","Effect":"Allow","Principal":"*","Action":"lambda:InvokeFunctionUrl",
+			"Condition":{"StringEquals":{"lambda:FunctionUrlAuthType":"NONE"}}},
+		{"Sid":"account","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::210987654321:root"},"Action":"lambda:InvokeFunction",
+			"Condition":{"StringEquals":{"aws:sourceaccount":"210987654321"}}},
+		{"Sid":"denied","Effect":"Deny","Principal":"*","Action":"lambda:InvokeFunction"}
+	]}`
+
+	permissions, err := parsePolicy(policy)
+	if err != nil {
+		t.Fatalf("parsing policy: %v", err)
+	}
+
+	want := []policyPermission{
+		{sid: "s3", principal: "S3", source: "arn:aws:s3:::uploads", action: "lambda:InvokeFunction"},
+		{sid: "rule", principal: "EventBridge, custom.example.com", action: "lambda:InvokeFunction"},
+		{sid: "url", principal: "*", source: "function URL, auth NONE", action: "lambda:InvokeFunctionUrl"},
+		{sid: "account", principal: "arn:aws:iam::210987654321:root", source: "account 210987654321", action: "lambda:InvokeFunction"},
+	}
+
+	if !slices.Equal(permissions, want) {
+		t.Fatalf("got %+v, want %+v", permissions, want)
+	}
+
+	if _, err := parsePolicy("{"); err == nil {
+		t.Fatal("expected an error for an invalid policy")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type versionsReq struct {
+	region string
+	name   string
+}
+
+type versionsMsg struct {
+	versions []lambdaVersion
+	aliases  []lambdaAlias
+}
+
+// A versionItem is a version or alias in the versions view.
+type versionItem struct {
+	title       string
+	description string
+	// qualifier is the version number or alias name.
+	qualifier string
+	// versions are the versions invocations of the item are routed to.
+	versions []string
+}
+
+func (v versionItem) Title() string {
+	return v.title
+}
+
+func (v versionItem) Description() string {
+	return v.description
+}
+
+func (v versionItem) FilterValue() string {
+	return v.qualifier
+}
+
+// listVersions requests the versions and aliases of the active function.
+func (m *model) listVersions() tea.Cmd {
+	target, ok := m.findLambdaItem(m.activeLambda, m.activeLambdaRegion)
+	if !ok {
+		return nil
+	}
+
+	select {
+	case m.reqCh <- m.tag(versionsReq{region: target.region, name: target.name}):
+		cmd := m.startLoading()
+		m.versionsTarget = target
+
+		return cmd
+	default:
+		return nil
+	}
+}
+
+func (m model) findLambdaItem(name string, region string) (lambdaItem, bool) {
+	for _, item := range m.lambdas.Items() {
+		if item, ok := item.(lambdaItem); ok && item.name == name && item.region == region {
+			return item, true
+		}
+	}
+
+	return lambdaItem{}, false
+}
+
+func (m model) viewVersionsUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if m.versions.FilterState() == list.Filtering {
+		m.versions, cmd = m.versions.Update(msg)
+
+		return m, cmd
+	}
+
+	m.versions, cmd = m.versions.Update(msg)
+	selectedItem, hasSelectedItem := m.versions.SelectedItem().(versionItem)
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.back):
+			cmd = nil
+			// The versions are listed from the details of the function,
+			// which replace those of a version or alias opened since.
+			if m.versions.FilterValue() == "" {
+				cmd = m.openLambdaDetail(m.versionsTarget)
+			}
+			m.versions.ResetFilter()
+		case key.Matches(msg, m.keys.choose):
+			if !hasSelectedItem {
+				break
+			}
+
+			select {
+			case m.reqCh <- m.tag(lambdaDetailReq{
+				region:    m.versionsTarget.region,
+				name:      m.versionsTarget.name,
+				qualifier: selectedItem.qualifier,
+			}):
+				cmd = m.startLoading()
+				m.activeLambda = m.versionsTarget.name
+				m.activeLambdaRegion = m.versionsTarget.region
+				m.activeLambdaQualifier = selectedItem.qualifier
+			default:
+			}
+		case key.Matches(msg, m.keys.logStreams):
+			if !hasSelectedItem {
+				break
+			}
+
+			select {
+			case m.reqCh <- m.tag(logStreamReq{
+				region:   m.versionsTarget.region,
+				logGroup: m.versionsTarget.logGroup,
+				versions: selectedItem.versions,
+			}):
+				cmd = m.startLoading()
+				m.activeLogStream = ""
+				m.activeLogGroup = m.versionsTarget.logGroup
+				m.activeLogRegion = m.versionsTarget.region
+				m.logStreamQualifier = selectedItem.title
+				m.logStreamVersions = selectedItem.versions
+			default:
+			}
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *model) onRcvVersionsMsg(msg versionsMsg) {
+	items := make([]list.Item, 0, len(msg.aliases)+len(msg.versions))
+
+	for _, alias := range msg.aliases {
+		items = append(items, versionItem{
+			title:       fmt.Sprintf("alias %s → %s", alias.name, formatAliasRouting(alias)),
+			description: cmp.Or(alias.description, "No description"),
+			qualifier:   alias.name,
+			versions:    slices.Sorted(maps.Keys(alias.weights)),
+		})
+	}
+
+	// $LATEST first, then the most recently published versions.
+	versions := slices.Clone(msg.versions)
+	slices.SortStableFunc(versions, func(a, b lambdaVersion) int {
+		return cmp.Compare(versionNumber(b.version), versionNumber(a.version))
+	})
+
+	for _, version := range versions {
+		items = append(items, versionItem{
+			title: "version " + version.version,
+			description: fmt.Sprintf(
+				"%s - Code SHA256: %s - Last Modified: %s",
+				cmp.Or(version.description, "No description"),
+				version.codeSha256,
+				version.lastModified,
+			),
+			qualifier: version.version,
+			versions:  []string{version.version},
+		})
+	}
+
+	m.versions.ResetFilter()
+	m.versions.SetItems(items)
+	m.versions.ResetSelected()
+	m.versions.Title = fmt.Sprintf("Versions and Aliases - Function \"%s\" - %s", m.versionsTarget.name, m.sessionTitle())
+	m.activeView = viewVersions
+	m.loading = false
+}
+
+// formatAliasRouting lists the versions of an alias along with their weights
+// if the alias routes to more than one version.
+func formatAliasRouting(alias lambdaAlias) string {
+	if len(alias.weights) <= 1 {
+		return alias.version
+	}
+
+	versions := slices.SortedFunc(maps.Keys(alias.weights), func(a, b string) int {
+		return cmp.Compare(alias.weights[b], alias.weights[a])
+	})
+
+	routes := make([]string, 0, len(versions))
+	for _, version := range versions {
+		routes = append(routes, fmt.Sprintf("%s (%.0f%%)", version, alias.weights[version]*100))
+	}
+
+	return strings.Join(routes, ", ")
+}
+
+// versionNumber orders $LATEST above all published versions.
+func versionNumber(version string) int {
+	n, err := strconv.Atoi(version)
+	if err != nil {
+		return math.MaxInt
+	}
+
+	return n
+}